@@ -0,0 +1,282 @@
+package semanticpen
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pushkarsingh32/semanticpen-go-sdk/internal/htmlnode"
+)
+
+// DefaultWordsPerMinute is the reading speed used for Analysis.ReadingTime
+const DefaultWordsPerMinute = 238
+
+// AnalyzeOptions configures Analyze
+type AnalyzeOptions struct {
+	TargetKeyword  string   // Keyword the article was generated for; Article does not carry it
+	Keywords       []string // Additional keywords to measure; SEOData.Keywords are always included
+	WordsPerMinute int      // Reading speed; defaults to DefaultWordsPerMinute
+}
+
+// Analysis holds quality metrics for an article
+type Analysis struct {
+	WordCount          int              `json:"wordCount"`
+	SentenceCount      int              `json:"sentenceCount"`
+	ParagraphCount     int              `json:"paragraphCount"`
+	ReadingTime        time.Duration    `json:"readingTime"`
+	FleschReadingEase  float64          `json:"fleschReadingEase"`  // 0-100, higher is easier; computed over body text, not headings
+	FleschKincaidGrade float64          `json:"fleschKincaidGrade"` // US school grade level
+	Headings           []Heading        `json:"headings,omitempty"` // Every heading in document order, including <h1>
+	Keywords           []KeywordDensity `json:"keywords,omitempty"`
+}
+
+// KeywordDensity reports how often a keyword phrase appears in the article
+type KeywordDensity struct {
+	Keyword string  `json:"keyword"`
+	Count   int     `json:"count"`
+	Density float64 `json:"density"` // Percentage of all words taken up by the phrase
+}
+
+// Analyze computes word, sentence and paragraph counts, reading time,
+// readability scores, heading structure and keyword density. The article's
+// HTML is used when present, otherwise its ArticleJSON.
+func Analyze(article *Article, options *AnalyzeOptions) (*Analysis, error) {
+	var blocks []textBlock
+	switch {
+	case article == nil:
+		return nil, &ValidationError{Field: "article", Message: "article is required"}
+	case article.ArticleHTML != "":
+		blocks = htmlTextBlocks(article.ArticleHTML)
+	case len(article.ArticleJSON) > 0:
+		blocks = contentTextBlocks(ParseArticleContent(article.ArticleJSON))
+	default:
+		return nil, ErrNoArticleContent
+	}
+
+	if options == nil {
+		options = &AnalyzeOptions{}
+	}
+	wordsPerMinute := options.WordsPerMinute
+	if wordsPerMinute <= 0 {
+		wordsPerMinute = DefaultWordsPerMinute
+	}
+
+	analysis := &Analysis{}
+	var words []string
+	var proseWords, syllables int
+	for _, block := range blocks {
+		blockWords := splitWords(block.text)
+		words = append(words, blockWords...)
+
+		if block.level > 0 {
+			analysis.Headings = append(analysis.Headings, Heading{Text: block.text, Level: block.level})
+			continue
+		}
+		if block.paragraph {
+			analysis.ParagraphCount++
+		}
+		analysis.SentenceCount += countSentences(block.text)
+		proseWords += len(blockWords)
+		for _, word := range blockWords {
+			syllables += countSyllables(word)
+		}
+	}
+
+	analysis.WordCount = len(words)
+	analysis.ReadingTime = (time.Duration(analysis.WordCount) * time.Minute / time.Duration(wordsPerMinute)).Round(time.Second)
+
+	if proseWords > 0 && analysis.SentenceCount > 0 {
+		wordsPerSentence := float64(proseWords) / float64(analysis.SentenceCount)
+		syllablesPerWord := float64(syllables) / float64(proseWords)
+		analysis.FleschReadingEase = 206.835 - 1.015*wordsPerSentence - 84.6*syllablesPerWord
+		analysis.FleschKincaidGrade = 0.39*wordsPerSentence + 11.8*syllablesPerWord - 15.59
+	}
+
+	keywords := []string{options.TargetKeyword}
+	keywords = append(keywords, options.Keywords...)
+	if article.SEOData != nil {
+		keywords = append(keywords, article.SEOData.Keywords...)
+	}
+	analysis.Keywords = keywordDensities(words, keywords)
+
+	return analysis, nil
+}
+
+// textBlock is a run of text from one block-level element
+type textBlock struct {
+	text      string
+	level     int  // Heading level, 0 for body text
+	paragraph bool // Text came from a paragraph rather than a list item, cell, etc.
+}
+
+// htmlTextBlocks splits HTML into the text of its block-level elements
+func htmlTextBlocks(source string) []textBlock {
+	var blocks []textBlock
+	var buf strings.Builder
+
+	flush := func(level int, paragraph bool) {
+		text := strings.TrimSpace(whitespaceRun.ReplaceAllString(buf.String(), " "))
+		buf.Reset()
+		if text != "" {
+			blocks = append(blocks, textBlock{text: text, level: level, paragraph: paragraph})
+		}
+	}
+
+	var collect func(n *htmlnode.Node, paragraph bool)
+	collect = func(n *htmlnode.Node, paragraph bool) {
+		for _, c := range n.Children {
+			switch {
+			case c.Type == htmlnode.TextNode:
+				buf.WriteString(c.Data)
+			case c.Type != htmlnode.ElementNode || markdownSkipped[c.Data]:
+			case c.Data == "br":
+				// A line break continues the current block
+				buf.WriteString(" ")
+			case headingLevel(c.Data) > 0:
+				flush(0, paragraph)
+				buf.WriteString(c.Text())
+				flush(headingLevel(c.Data), false)
+			case markdownBlockElements[c.Data] || c.Data == "td" || c.Data == "th":
+				flush(0, paragraph)
+				collect(c, c.Data == "p")
+				flush(0, c.Data == "p")
+			default:
+				collect(c, paragraph)
+			}
+		}
+	}
+
+	collect(htmlnode.Parse(source), false)
+	flush(0, false)
+	return blocks
+}
+
+// contentTextBlocks flattens typed article content into text blocks
+func contentTextBlocks(content *ArticleContent) []textBlock {
+	var blocks []textBlock
+	add := func(text string, level int, paragraph bool) {
+		if text = strings.TrimSpace(text); text != "" {
+			blocks = append(blocks, textBlock{text: text, level: level, paragraph: paragraph})
+		}
+	}
+	addBlocks := func(items []Block) {
+		for _, block := range items {
+			add(block.Text, 0, block.Type == BlockParagraph)
+			for _, item := range block.Items {
+				add(item, 0, false)
+			}
+		}
+	}
+
+	add(content.Title, 1, false)
+	addBlocks(content.Introduction)
+	content.WalkSections(func(section *Section, depth int) error {
+		add(section.Heading.Text, section.Heading.Level, false)
+		addBlocks(section.Blocks)
+		return nil
+	})
+	addBlocks(content.Conclusion)
+	for _, faq := range content.FAQs {
+		add(faq.Question, 0, false)
+		add(faq.Answer, 0, true)
+	}
+	for _, takeaway := range content.KeyTakeaways {
+		add(takeaway, 0, false)
+	}
+	return blocks
+}
+
+// headingLevel returns 1-6 for h1-h6 and 0 for any other tag
+func headingLevel(tag string) int {
+	if len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6' {
+		return int(tag[1] - '0')
+	}
+	return 0
+}
+
+var sentenceEnd = regexp.MustCompile(`[.!?]+(["')\]]*)(\s+|$)`)
+
+// countSentences counts sentence terminators, treating trailing text without
+// one (a list item, a caption) as a sentence of its own
+func countSentences(text string) int {
+	ends := sentenceEnd.FindAllStringIndex(text, -1)
+	count := len(ends)
+	if count == 0 || strings.TrimSpace(text[ends[count-1][1]:]) != "" {
+		count++
+	}
+	return count
+}
+
+// splitWords returns the lowercased words of text, keeping inner apostrophes and hyphens
+func splitWords(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’' && r != '-'
+	})
+
+	words := fields[:0]
+	for _, field := range fields {
+		if word := strings.Trim(field, "'’-"); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// countSyllables estimates the syllables in an English word by counting vowel
+// groups, discounting a silent trailing e
+func countSyllables(word string) int {
+	count := 0
+	previousVowel := false
+	for _, r := range word {
+		vowel := strings.ContainsRune("aeiouy", r)
+		if vowel && !previousVowel {
+			count++
+		}
+		previousVowel = vowel
+	}
+	if strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le") && count > 1 {
+		count--
+	}
+	if count == 0 {
+		count = 1
+	}
+	return count
+}
+
+// keywordDensities counts each distinct keyword phrase in words
+func keywordDensities(words []string, keywords []string) []KeywordDensity {
+	var densities []KeywordDensity
+	seen := make(map[string]bool)
+	for _, keyword := range keywords {
+		phrase := splitWords(keyword)
+		key := strings.Join(phrase, " ")
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		count := 0
+		for i := 0; i+len(phrase) <= len(words); i++ {
+			if matchPhrase(words[i:], phrase) {
+				count++
+			}
+		}
+
+		density := KeywordDensity{Keyword: strings.TrimSpace(keyword), Count: count}
+		if len(words) > 0 {
+			density.Density = float64(count*len(phrase)) / float64(len(words)) * 100
+		}
+		densities = append(densities, density)
+	}
+	return densities
+}
+
+func matchPhrase(words, phrase []string) bool {
+	for i, word := range phrase {
+		if words[i] != word {
+			return false
+		}
+	}
+	return true
+}
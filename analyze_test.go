@@ -0,0 +1,45 @@
+package semanticpen
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAnalyzeCounts(t *testing.T) {
+	tests := []struct {
+		name       string
+		html       string
+		words      int
+		sentences  int
+		paragraphs int
+	}{
+		{name: "line break within paragraph", html: "<p>One.<br>Two.</p>", words: 2, sentences: 2, paragraphs: 1},
+		{name: "separate paragraphs", html: "<p>One.</p><p>Two.</p>", words: 2, sentences: 2, paragraphs: 2},
+		{name: "break between inline text", html: "<p>Roses are red<br/>violets are blue</p>", words: 6, sentences: 1, paragraphs: 1},
+		{name: "list items are not paragraphs", html: "<p>Intro.</p><ul><li>First</li><li>Second</li></ul>", words: 3, sentences: 3, paragraphs: 1},
+		{name: "headings are not paragraphs", html: "<h2>Title</h2><p>Body text here.</p>", words: 4, sentences: 1, paragraphs: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis, err := Analyze(&Article{ArticleHTML: tt.html}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if analysis.WordCount != tt.words || analysis.SentenceCount != tt.sentences || analysis.ParagraphCount != tt.paragraphs {
+				t.Errorf("Analyze() = %d words, %d sentences, %d paragraphs; want %d, %d, %d",
+					analysis.WordCount, analysis.SentenceCount, analysis.ParagraphCount, tt.words, tt.sentences, tt.paragraphs)
+			}
+		})
+	}
+}
+
+func TestAnalyzeErrors(t *testing.T) {
+	var validationErr *ValidationError
+	if _, err := Analyze(nil, nil); !errors.As(err, &validationErr) {
+		t.Errorf("Analyze(nil) error = %v, want a ValidationError", err)
+	}
+	if _, err := Analyze(&Article{}, nil); !errors.Is(err, ErrNoArticleContent) {
+		t.Errorf("Analyze(empty) error = %v, want ErrNoArticleContent", err)
+	}
+}
@@ -1,15 +1,22 @@
 package semanticpen
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 )
 
 // GenerateArticle generates a new article with the given target keyword and options
 func (c *Client) GenerateArticle(targetKeyword string, options *GenerateArticleRequest) (*GenerateArticleResponse, error) {
+	return c.GenerateArticleWithContext(context.Background(), targetKeyword, options)
+}
+
+// GenerateArticleWithContext is like GenerateArticle but honors ctx cancellation
+func (c *Client) GenerateArticleWithContext(ctx context.Context, targetKeyword string, options *GenerateArticleRequest) (*GenerateArticleResponse, error) {
 	if targetKeyword == "" {
 		return nil, &ValidationError{
 			Field:   "targetKeyword",
@@ -17,6 +24,15 @@ func (c *Client) GenerateArticle(targetKeyword string, options *GenerateArticleR
 		}
 	}
 
+	if options != nil && options.WebhookURL != "" {
+		if u, err := url.Parse(options.WebhookURL); err != nil || !u.IsAbs() || u.Host == "" {
+			return nil, &ValidationError{
+				Field:   "webhookUrl",
+				Message: "webhook URL must be an absolute URL",
+			}
+		}
+	}
+
 	request := &GenerateArticleRequest{
 		TargetKeyword: targetKeyword,
 	}
@@ -26,9 +42,30 @@ func (c *Client) GenerateArticle(targetKeyword string, options *GenerateArticleR
 		request.SEO = options.SEO
 		request.Writing = options.Writing
 		request.Advanced = options.Advanced
+		request.WebhookURL = options.WebhookURL
+		request.IdempotencyKey = options.IdempotencyKey
+	}
+
+	if request.IdempotencyKey == "" && c.retry != nil && c.retry.RetryIdempotentPosts {
+		request.IdempotencyKey = newIdempotencyKey()
+	}
+	if request.IdempotencyKey == "" {
+		return c.generateArticle(ctx, request)
 	}
 
-	resp, err := c.makeRequest("POST", "/api/articles", request)
+	return c.idempotency.do(ctx, request.IdempotencyKey, func() (*GenerateArticleResponse, error) {
+		return c.generateArticle(ctx, request)
+	})
+}
+
+// generateArticle submits a generation request to the API
+func (c *Client) generateArticle(ctx context.Context, request *GenerateArticleRequest) (*GenerateArticleResponse, error) {
+	var header http.Header
+	if request.IdempotencyKey != "" {
+		header = http.Header{IdempotencyKeyHeader: []string{request.IdempotencyKey}}
+	}
+
+	resp, err := c.makeRequest(ctx, "POST", "/api/articles", request, header)
 	if err != nil {
 		return nil, err
 	}
@@ -39,12 +76,10 @@ func (c *Client) GenerateArticle(targetKeyword string, options *GenerateArticleR
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	if c.debug {
-		fmt.Printf("[DEBUG] Response: %s\n", string(body))
-	}
+	c.logBody("semanticpen response body", "/api/articles", body)
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, c.parseErrorResponse(resp.StatusCode, body)
+		return nil, c.parseErrorResponse(resp, body)
 	}
 
 	var result GenerateArticleResponse
@@ -52,11 +87,24 @@ func (c *Client) GenerateArticle(targetKeyword string, options *GenerateArticleR
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	c.logger.Info("semanticpen article generation started",
+		"article_id", result.ArticleID, "article_ids", result.ArticleIDs, "project_id", result.ProjectID)
+
+	submittedAt := time.Now()
+	for _, id := range result.GetArticleIDs() {
+		c.saveJob(Job{ArticleID: id, Request: request, Status: StatusPending, SubmittedAt: submittedAt})
+	}
+
 	return &result, nil
 }
 
 // GetArticle retrieves an article by its ID
 func (c *Client) GetArticle(articleID string) (*Article, error) {
+	return c.GetArticleWithContext(context.Background(), articleID)
+}
+
+// GetArticleWithContext is like GetArticle but honors ctx cancellation
+func (c *Client) GetArticleWithContext(ctx context.Context, articleID string) (*Article, error) {
 	if articleID == "" {
 		return nil, &ValidationError{
 			Field:   "articleID",
@@ -65,7 +113,7 @@ func (c *Client) GetArticle(articleID string) (*Article, error) {
 	}
 
 	endpoint := fmt.Sprintf("/api/articles/%s", articleID)
-	resp, err := c.makeRequest("GET", endpoint, nil)
+	resp, err := c.makeRequest(ctx, "GET", endpoint, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -76,12 +124,10 @@ func (c *Client) GetArticle(articleID string) (*Article, error) {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	if c.debug {
-		fmt.Printf("[DEBUG] Response: %s\n", string(body))
-	}
+	c.logBody("semanticpen response body", endpoint, body)
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, c.parseErrorResponse(resp.StatusCode, body)
+		return nil, c.parseErrorResponse(resp, body)
 	}
 
 	var article Article
@@ -89,11 +135,19 @@ func (c *Client) GetArticle(articleID string) (*Article, error) {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	c.logger.Debug("semanticpen article fetched",
+		"article_id", articleID, "status", article.Status, "progress", article.Progress)
+
 	return &article, nil
 }
 
 // DeleteArticle deletes an article by its ID
 func (c *Client) DeleteArticle(articleID string) error {
+	return c.DeleteArticleWithContext(context.Background(), articleID)
+}
+
+// DeleteArticleWithContext is like DeleteArticle but honors ctx cancellation
+func (c *Client) DeleteArticleWithContext(ctx context.Context, articleID string) error {
 	if articleID == "" {
 		return &ValidationError{
 			Field:   "articleID",
@@ -102,7 +156,7 @@ func (c *Client) DeleteArticle(articleID string) error {
 	}
 
 	endpoint := fmt.Sprintf("/api/articles/%s", articleID)
-	resp, err := c.makeRequest("DELETE", endpoint, nil)
+	resp, err := c.makeRequest(ctx, "DELETE", endpoint, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -110,29 +164,24 @@ func (c *Client) DeleteArticle(articleID string) error {
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
 		body, _ := io.ReadAll(resp.Body)
-		return c.parseErrorResponse(resp.StatusCode, body)
+		return c.parseErrorResponse(resp, body)
 	}
 
+	c.deleteJob(articleID)
 	return nil
 }
 
-// GenerateArticleAndWait generates an article and waits for it to complete
+// GenerateArticleAndWait generates an article and waits for it to complete.
+// When the API returns several article IDs only the first is awaited; use
+// GenerateArticlesAndWait to wait for all of them.
 func (c *Client) GenerateArticleAndWait(targetKeyword string, options *GenerateArticleRequest, waitOptions *GenerateAndWaitOptions) (*Article, error) {
-	if waitOptions == nil {
-		waitOptions = &GenerateAndWaitOptions{
-			MaxAttempts: 60,
-			Interval:    5 * time.Second,
-		}
-	}
-
-	if waitOptions.MaxAttempts == 0 {
-		waitOptions.MaxAttempts = 60
-	}
-	if waitOptions.Interval == 0 {
-		waitOptions.Interval = 5 * time.Second
-	}
+	return c.GenerateArticleAndWaitWithContext(context.Background(), targetKeyword, options, waitOptions)
+}
 
-	result, err := c.GenerateArticle(targetKeyword, options)
+// GenerateArticleAndWaitWithContext is like GenerateArticleAndWait but honors ctx
+// cancellation during both generation and polling
+func (c *Client) GenerateArticleAndWaitWithContext(ctx context.Context, targetKeyword string, options *GenerateArticleRequest, waitOptions *GenerateAndWaitOptions) (*Article, error) {
+	result, err := c.GenerateArticleWithContext(ctx, targetKeyword, options)
 	if err != nil {
 		return nil, err
 	}
@@ -142,46 +191,92 @@ func (c *Client) GenerateArticleAndWait(targetKeyword string, options *GenerateA
 		return nil, err
 	}
 
-	return c.WaitForArticle(articleID, waitOptions)
+	return c.WaitForArticleWithContext(ctx, articleID, waitOptions)
 }
 
 // WaitForArticle waits for an article to complete generation
 func (c *Client) WaitForArticle(articleID string, options *GenerateAndWaitOptions) (*Article, error) {
-	if options == nil {
-		options = &GenerateAndWaitOptions{
-			MaxAttempts: 60,
-			Interval:    5 * time.Second,
-		}
+	return c.WaitForArticleWithContext(context.Background(), articleID, options)
+}
+
+// WaitForArticleWithContext waits for an article to complete generation, returning
+// ctx.Err() as soon as ctx is cancelled, including while sleeping between polls
+func (c *Client) WaitForArticleWithContext(ctx context.Context, articleID string, options *GenerateAndWaitOptions) (*Article, error) {
+	options = options.withDefaults()
+
+	start := time.Now()
+	var deadline time.Time
+	if options.Timeout > 0 {
+		deadline = start.Add(options.Timeout)
 	}
 
-	for attempt := 1; attempt <= options.MaxAttempts; attempt++ {
-		article, err := c.GetArticle(articleID)
+	var previous ArticleStatus
+	for attempt := 1; ; attempt++ {
+		article, err := c.GetArticleWithContext(ctx, articleID)
 		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
 			return nil, err
 		}
 
 		if options.OnProgress != nil {
-			options.OnProgress(attempt, article.Status)
+			options.OnProgress(attempt, string(article.Status))
+		}
+		if options.OnProgressEvent != nil {
+			options.OnProgressEvent(newProgressEvent(attempt, previous, time.Since(start), article))
 		}
 
+		if !article.Status.IsKnown() {
+			return nil, &UnknownStatusError{ArticleID: articleID, Status: article.Status}
+		}
+		if previous != "" && !previous.CanTransitionTo(article.Status) {
+			return nil, &StatusRegressionError{ArticleID: articleID, Previous: previous, Status: article.Status}
+		}
+		if article.Status != previous {
+			c.saveJob(Job{ArticleID: articleID, Status: article.Status, Error: article.ErrorMessage})
+		}
+		previous = article.Status
+
 		switch article.Status {
-		case "finished":
+		case StatusFinished:
+			if options.Sanitize != nil {
+				article.SanitizeHTML(options.Sanitize)
+			}
 			return article, nil
-		case "failed":
+		case StatusFailed:
 			return nil, fmt.Errorf("article generation failed: %s", article.ErrorMessage)
-		case "pending", "processing":
-			if attempt < options.MaxAttempts {
-				time.Sleep(options.Interval)
-				continue
+		}
+
+		timeout := &TimeoutError{ArticleID: articleID, Attempts: attempt, Timeout: options.Timeout}
+		if options.MaxAttempts > 0 && attempt >= options.MaxAttempts {
+			return nil, timeout
+		}
+
+		delay := options.PollStrategy.NextInterval(PollState{Attempt: attempt, Elapsed: time.Since(start), Article: article})
+		if !deadline.IsZero() {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return nil, timeout
+			}
+			if delay > remaining {
+				delay = remaining
 			}
 		}
-	}
 
-	return nil, fmt.Errorf("article generation timeout after %d attempts", options.MaxAttempts)
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
 }
 
-// parseErrorResponse parses API error responses
-func (c *Client) parseErrorResponse(statusCode int, body []byte) error {
+// parseErrorResponse parses API error responses, mapping 429 responses to *RateLimitError
+func (c *Client) parseErrorResponse(resp *http.Response, body []byte) error {
+	statusCode := resp.StatusCode
+	if statusCode == http.StatusTooManyRequests {
+		return newRateLimitError(resp.Header, body)
+	}
+
 	var apiErr APIError
 	if err := json.Unmarshal(body, &apiErr); err != nil {
 		return &APIError{
@@ -192,4 +287,17 @@ func (c *Client) parseErrorResponse(statusCode int, body []byte) error {
 
 	apiErr.StatusCode = statusCode
 	return &apiErr
-}
\ No newline at end of file
+}
+
+// sleepContext pauses for d or until ctx is done, whichever comes first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
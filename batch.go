@@ -0,0 +1,317 @@
+package semanticpen
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultBatchConcurrency is the number of batch items processed at the same
+// time when BatchOptions.Concurrency is not set
+const DefaultBatchConcurrency = 4
+
+// ErrBatchStarted is returned when Run or RunStream is called on a batch that
+// has already been started; a Batch is single-use
+var ErrBatchStarted = errors.New("batch already started")
+
+// BatchItemStatus is the final outcome of a single batch item
+type BatchItemStatus string
+
+const (
+	BatchSucceeded BatchItemStatus = "succeeded"
+	BatchFailed    BatchItemStatus = "failed"
+	BatchTimedOut  BatchItemStatus = "timed_out"
+	BatchCancelled BatchItemStatus = "cancelled"
+)
+
+// BatchOptions configures a batch run
+type BatchOptions struct {
+	Concurrency int                     // Items submitted and awaited at the same time; defaults to DefaultBatchConcurrency
+	SubmitRate  *RateLimit              // Throttle for generation requests, on top of the client's own limiter
+	Wait        *GenerateAndWaitOptions // Polling options applied to every generated article
+}
+
+// BatchResult is the outcome of one request in a batch
+type BatchResult struct {
+	Index      int                     // Position of the request in the input
+	Request    *GenerateArticleRequest // The submitted request
+	ArticleIDs []string                // IDs returned by the API, empty if submission failed
+	Articles   []*Article              // Finished articles, in the same order as ArticleIDs
+	Status     BatchItemStatus
+	Err        error
+}
+
+// BatchSummary tallies the outcomes of a batch
+type BatchSummary struct {
+	Total     int
+	Succeeded []string // Target keywords that finished
+	Failed    []string // Target keywords whose submission or generation failed
+	TimedOut  []string // Target keywords still generating when polling gave up
+	Cancelled []string // Target keywords abandoned because the batch was cancelled
+	Duration  time.Duration
+}
+
+// Batch runs many article generations with bounded concurrency, streaming
+// per-item results as they complete. It can be paused, resumed and cancelled
+// while running.
+type Batch struct {
+	client  *Client
+	options BatchOptions
+	submit  *tokenBucket
+
+	mu      sync.Mutex
+	started bool
+	cancel  context.CancelFunc
+	resume  chan struct{} // non-nil while paused; closed on Resume
+	summary BatchSummary
+	start   time.Time
+	end     time.Time // Set once every result has been sent
+}
+
+// NewBatch creates a batch that generates articles with the given client
+func NewBatch(client *Client, options *BatchOptions) *Batch {
+	b := &Batch{client: client}
+	if options != nil {
+		b.options = *options
+	}
+	if b.options.Concurrency <= 0 {
+		b.options.Concurrency = DefaultBatchConcurrency
+	}
+	b.submit = newTokenBucket(b.options.SubmitRate)
+	return b
+}
+
+// Run processes the given requests and streams a result for each of them.
+// The returned channel is closed once every request has been handled; callers
+// must drain it for the batch to make progress.
+func (b *Batch) Run(ctx context.Context, requests []GenerateArticleRequest) (<-chan BatchResult, error) {
+	input := make(chan GenerateArticleRequest)
+	results, err := b.RunStream(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		defer close(input)
+		for _, request := range requests {
+			input <- request
+		}
+	}()
+
+	return results, nil
+}
+
+// RunStream is like Run but reads requests from a channel until it is closed,
+// so callers can feed the batch while it runs
+func (b *Batch) RunStream(ctx context.Context, requests <-chan GenerateArticleRequest) (<-chan BatchResult, error) {
+	b.mu.Lock()
+	if b.started {
+		b.mu.Unlock()
+		return nil, ErrBatchStarted
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	b.started = true
+	b.cancel = cancel
+	b.start = time.Now()
+	b.mu.Unlock()
+
+	type item struct {
+		index   int
+		request GenerateArticleRequest
+	}
+
+	items := make(chan item)
+	results := make(chan BatchResult, b.options.Concurrency)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < b.options.Concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for it := range items {
+				request := it.request
+				result := b.process(ctx, it.index, &request)
+				b.record(result)
+				results <- result
+			}
+		}()
+	}
+
+	go func() {
+		defer close(results)
+		defer cancel()
+
+		index := 0
+		for request := range requests {
+			request := request
+			if ctx.Err() != nil {
+				result := BatchResult{Index: index, Request: &request, Status: BatchCancelled, Err: ctx.Err()}
+				b.record(result)
+				results <- result
+				index++
+				continue
+			}
+			items <- item{index: index, request: request}
+			index++
+		}
+		close(items)
+		wg.Wait()
+
+		b.mu.Lock()
+		b.end = time.Now()
+		b.mu.Unlock()
+	}()
+
+	return results, nil
+}
+
+// process submits a single request and waits for its articles
+func (b *Batch) process(ctx context.Context, index int, request *GenerateArticleRequest) BatchResult {
+	result := BatchResult{Index: index, Request: request}
+
+	if err := b.waitResumed(ctx); err != nil {
+		return b.fail(result, err)
+	}
+	if err := b.submit.wait(ctx); err != nil {
+		return b.fail(result, err)
+	}
+
+	response, err := b.client.GenerateArticleWithContext(ctx, request.TargetKeyword, request)
+	if err != nil {
+		return b.fail(result, err)
+	}
+
+	result.ArticleIDs = response.GetArticleIDs()
+	if len(result.ArticleIDs) == 0 {
+		return b.fail(result, errors.New("no articles were generated"))
+	}
+
+	waited, err := b.client.WaitForArticles(ctx, result.ArticleIDs, &WaitForArticlesOptions{
+		Concurrency: len(result.ArticleIDs),
+		Wait:        b.options.Wait,
+	})
+	for _, w := range waited {
+		result.Articles = append(result.Articles, w.Article)
+	}
+	if err != nil {
+		return b.fail(result, err)
+	}
+
+	result.Status = BatchSucceeded
+	return result
+}
+
+// fail classifies an error into the matching item status
+func (b *Batch) fail(result BatchResult, err error) BatchResult {
+	var timeoutErr *TimeoutError
+	switch {
+	case errors.As(err, &timeoutErr), errors.Is(err, context.DeadlineExceeded):
+		result.Status = BatchTimedOut
+	case errors.Is(err, context.Canceled):
+		result.Status = BatchCancelled
+	default:
+		result.Status = BatchFailed
+	}
+	result.Err = err
+	return result
+}
+
+// record adds a finished item to the running summary
+func (b *Batch) record(result BatchResult) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	keyword := result.Request.TargetKeyword
+	b.summary.Total++
+	switch result.Status {
+	case BatchSucceeded:
+		b.summary.Succeeded = append(b.summary.Succeeded, keyword)
+	case BatchTimedOut:
+		b.summary.TimedOut = append(b.summary.TimedOut, keyword)
+	case BatchCancelled:
+		b.summary.Cancelled = append(b.summary.Cancelled, keyword)
+	default:
+		b.summary.Failed = append(b.summary.Failed, keyword)
+	}
+}
+
+// waitResumed blocks while the batch is paused
+func (b *Batch) waitResumed(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		resume := b.resume
+		b.mu.Unlock()
+
+		if resume == nil {
+			return nil
+		}
+
+		select {
+		case <-resume:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Pause stops the batch from submitting new requests. Articles already
+// submitted keep being polled.
+func (b *Batch) Pause() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.resume == nil {
+		b.resume = make(chan struct{})
+	}
+}
+
+// Resume lets a paused batch continue submitting requests
+func (b *Batch) Resume() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.resume != nil {
+		close(b.resume)
+		b.resume = nil
+	}
+}
+
+// Paused reports whether the batch is currently paused
+func (b *Batch) Paused() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.resume != nil
+}
+
+// Cancel stops the batch. In-flight submissions and waits are aborted and
+// every remaining request is reported as cancelled.
+func (b *Batch) Cancel() {
+	b.mu.Lock()
+	cancel := b.cancel
+	b.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// Summary returns the outcome tally so far. Once the result channel is
+// closed it is the final summary of the batch.
+func (b *Batch) Summary() BatchSummary {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	summary := b.summary
+	summary.Succeeded = append([]string(nil), b.summary.Succeeded...)
+	summary.Failed = append([]string(nil), b.summary.Failed...)
+	summary.TimedOut = append([]string(nil), b.summary.TimedOut...)
+	summary.Cancelled = append([]string(nil), b.summary.Cancelled...)
+	switch {
+	case !b.end.IsZero():
+		summary.Duration = b.end.Sub(b.start)
+	case !b.start.IsZero():
+		summary.Duration = time.Since(b.start)
+	}
+	return summary
+}
@@ -0,0 +1,29 @@
+package semanticpen
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBatchSummaryDurationStopsAtCompletion(t *testing.T) {
+	b := NewBatch(nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := b.Run(ctx, []GenerateArticleRequest{{TargetKeyword: "a"}, {TargetKeyword: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range results {
+	}
+
+	first := b.Summary()
+	if len(first.Cancelled) != 2 {
+		t.Fatalf("Cancelled = %v, want both keywords", first.Cancelled)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if second := b.Summary(); second.Duration != first.Duration {
+		t.Errorf("Duration grew after the batch finished: %v then %v", first.Duration, second.Duration)
+	}
+}
@@ -0,0 +1,473 @@
+// Package bulk reads article requests from spreadsheets and writes batch
+// results back out.
+//
+// Input rows come from CSV (with a header row), JSON lines, or plain text with
+// one keyword per line. Columns are mapped onto GenerateArticleRequest fields
+// with a Mapping, and every row is validated before any is returned, so a bad
+// spreadsheet fails before a single article is paid for.
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/pushkarsingh32/semanticpen-go-sdk"
+)
+
+// Format is an input or output file format
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+	FormatLines Format = "lines" // One keyword per line; input only
+)
+
+// DetectFormat picks a format from a file extension: .csv, .jsonl or
+// .ndjson, and FormatLines for anything else
+func DetectFormat(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	}
+	return FormatLines
+}
+
+// ParseFormat converts a format name, accepting "ndjson" and "txt" as aliases
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "csv":
+		return FormatCSV, nil
+	case "jsonl", "ndjson":
+		return FormatJSONL, nil
+	case "lines", "txt", "text":
+		return FormatLines, nil
+	}
+	return "", fmt.Errorf("unknown format %q", name)
+}
+
+// Mapping names the input column (or JSON key) holding each request field.
+// Names are matched ignoring case, spaces, underscores and hyphens, so
+// "project_name" matches a "Project Name" header. Empty names are not read.
+type Mapping struct {
+	Keyword     string
+	Language    string
+	Country     string
+	Tone        string
+	Length      string
+	Keywords    string // SEO keywords, split on KeywordsSeparator in CSV; a string or array in JSON
+	ProjectName string
+
+	KeywordsSeparator string // Defaults to ","
+}
+
+// DefaultMapping returns the column names used when no mapping is given
+func DefaultMapping() *Mapping {
+	return &Mapping{
+		Keyword:           "keyword",
+		Language:          "language",
+		Country:           "country",
+		Tone:              "tone",
+		Length:            "length",
+		Keywords:          "keywords",
+		ProjectName:       "project_name",
+		KeywordsSeparator: ",",
+	}
+}
+
+// Set assigns the column for a field named like its Mapping field or its
+// column, e.g. "keyword", "project_name" or "projectName"
+func (m *Mapping) Set(field, column string) error {
+	switch normalize(field) {
+	case "keyword", "targetkeyword":
+		m.Keyword = column
+	case "language":
+		m.Language = column
+	case "country":
+		m.Country = column
+	case "tone":
+		m.Tone = column
+	case "length":
+		m.Length = column
+	case "keywords", "seokeywords":
+		m.Keywords = column
+	case "projectname", "project":
+		m.ProjectName = column
+	default:
+		return fmt.Errorf("unknown field %q", field)
+	}
+	return nil
+}
+
+// Options configures Read
+type Options struct {
+	Format   Format                              // Required by Read; ReadFile detects it from the file name when empty
+	Mapping  *Mapping                            // Defaults to DefaultMapping()
+	Defaults *semanticpen.GenerateArticleRequest // Copied into every row; values in the row take precedence
+}
+
+// Row is one validated request
+type Row struct {
+	Line    int // Line (or CSV record) number in the input, starting at 1
+	Request semanticpen.GenerateArticleRequest
+}
+
+// RowError is a problem with one input row
+type RowError struct {
+	Line    int
+	Field   string
+	Message string
+}
+
+func (e *RowError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", e.Line, e.Field, e.Message)
+}
+
+// RowErrors lists every invalid row in an input
+type RowErrors []*RowError
+
+func (e RowErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%d invalid rows; first: %s", len(e), e[0].Error())
+}
+
+// ErrNoRows is returned when an input contains no requests
+var ErrNoRows = errors.New("bulk: input contains no rows")
+
+// ReadFile reads requests from path, detecting the format from its extension
+// when options.Format is empty
+func ReadFile(path string, options *Options) ([]Row, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	opts := Options{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Format == "" {
+		opts.Format = DetectFormat(path)
+	}
+	return Read(f, &opts)
+}
+
+// Read parses and validates every row of r. When any row is invalid it
+// returns RowErrors describing all of them and no rows.
+func Read(r io.Reader, options *Options) ([]Row, error) {
+	if options == nil || options.Format == "" {
+		return nil, fmt.Errorf("bulk: format is required")
+	}
+	mapping := options.Mapping
+	if mapping == nil {
+		mapping = DefaultMapping()
+	}
+
+	var records []record
+	var err error
+	switch options.Format {
+	case FormatCSV:
+		records, err = readCSV(r)
+	case FormatJSONL:
+		records, err = readJSONL(r)
+	case FormatLines:
+		records, err = readLines(r, mapping)
+	default:
+		return nil, fmt.Errorf("bulk: unsupported input format %q", options.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	var errs RowErrors
+	for _, rec := range records {
+		if rec.err != nil {
+			errs = append(errs, rec.err)
+			continue
+		}
+		row, rowErrs := buildRow(rec, mapping, options.Defaults)
+		if len(rowErrs) > 0 {
+			errs = append(errs, rowErrs...)
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if len(rows) == 0 {
+		return nil, ErrNoRows
+	}
+	return rows, nil
+}
+
+// record is one raw input row keyed by normalized column name
+type record struct {
+	line   int
+	fields map[string]interface{}
+	err    *RowError
+}
+
+func readCSV(r io.Reader) ([]record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrNoRows
+	}
+	if err != nil {
+		return nil, fmt.Errorf("bulk: failed to read CSV header: %w", err)
+	}
+	columns := make([]string, len(header))
+	for i, name := range header {
+		columns[i] = normalize(strings.TrimPrefix(name, "\ufeff"))
+	}
+
+	var records []record
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				records = append(records, record{err: &RowError{Line: parseErr.StartLine, Message: parseErr.Err.Error()}})
+				continue
+			}
+			return nil, fmt.Errorf("bulk: failed to read CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlank(values) {
+			continue
+		}
+		if len(values) > len(columns) {
+			records = append(records, record{line: line, err: &RowError{Line: line,
+				Message: fmt.Sprintf("has %d columns, header has %d", len(values), len(columns))}})
+			continue
+		}
+
+		fields := make(map[string]interface{}, len(values))
+		for i, value := range values {
+			fields[columns[i]] = value
+		}
+		records = append(records, record{line: line, fields: fields})
+	}
+}
+
+func readJSONL(r io.Reader) ([]record, error) {
+	var records []record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10<<20)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var raw map[string]interface{}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			records = append(records, record{line: line, err: &RowError{Line: line, Message: "invalid JSON: " + err.Error()}})
+			continue
+		}
+		fields := make(map[string]interface{}, len(raw))
+		for key, value := range raw {
+			fields[normalize(key)] = value
+		}
+		records = append(records, record{line: line, fields: fields})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("bulk: failed to read JSON lines: %w", err)
+	}
+	return records, nil
+}
+
+// readLines treats each non-blank line not starting with # as a keyword
+func readLines(r io.Reader, mapping *Mapping) ([]record, error) {
+	var records []record
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		records = append(records, record{line: line, fields: map[string]interface{}{normalize(mapping.Keyword): text}})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("bulk: failed to read input: %w", err)
+	}
+	return records, nil
+}
+
+// buildRow maps a record onto a request, copying defaults first
+func buildRow(rec record, mapping *Mapping, defaults *semanticpen.GenerateArticleRequest) (Row, RowErrors) {
+	row := Row{Line: rec.line}
+	if defaults != nil {
+		row.Request = copyRequest(defaults)
+	}
+	request := &row.Request
+
+	var errs RowErrors
+	text := func(field, column string) string {
+		if column == "" {
+			return ""
+		}
+		value, ok := rec.fields[normalize(column)]
+		if !ok || value == nil {
+			return ""
+		}
+		switch v := value.(type) {
+		case string:
+			return strings.TrimSpace(v)
+		case float64, bool:
+			return fmt.Sprint(v)
+		}
+		errs = append(errs, &RowError{Line: rec.line, Field: field, Message: "must be a string"})
+		return ""
+	}
+
+	keyword := text("keyword", mapping.Keyword)
+	if keyword == "" {
+		keyword = request.TargetKeyword
+	}
+	if keyword == "" {
+		errs = append(errs, &RowError{Line: rec.line, Field: "keyword", Message: "is required"})
+	}
+	request.TargetKeyword = keyword
+
+	if v := text("language", mapping.Language); v != "" {
+		generation(request).Language = v
+	}
+	if v := text("country", mapping.Country); v != "" {
+		generation(request).Country = v
+	}
+	if v := text("project_name", mapping.ProjectName); v != "" {
+		generation(request).ProjectName = v
+	}
+	if v := text("tone", mapping.Tone); v != "" {
+		writing(request).Tone = v
+	}
+	if v := text("length", mapping.Length); v != "" {
+		writing(request).Length = v
+	}
+
+	if mapping.Keywords != "" {
+		keywords, err := keywordList(rec.fields[normalize(mapping.Keywords)], mapping.KeywordsSeparator)
+		if err != nil {
+			errs = append(errs, &RowError{Line: rec.line, Field: "keywords", Message: err.Error()})
+		} else if len(keywords) > 0 {
+			if request.SEO == nil {
+				request.SEO = &semanticpen.SEOOptions{}
+			}
+			request.SEO.Keywords = keywords
+		}
+	}
+
+	return row, errs
+}
+
+// keywordList accepts a separated string or, from JSON, an array of strings
+func keywordList(value interface{}, separator string) ([]string, error) {
+	if separator == "" {
+		separator = ","
+	}
+
+	var items []string
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		items = strings.Split(v, separator)
+	case []interface{}:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("must be a list of strings")
+			}
+			items = append(items, s)
+		}
+	default:
+		return nil, fmt.Errorf("must be a string or a list of strings")
+	}
+
+	var keywords []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			keywords = append(keywords, item)
+		}
+	}
+	return keywords, nil
+}
+
+// copyRequest copies defaults so rows never share option structs
+func copyRequest(defaults *semanticpen.GenerateArticleRequest) semanticpen.GenerateArticleRequest {
+	request := *defaults
+	if defaults.Generation != nil {
+		generation := *defaults.Generation
+		request.Generation = &generation
+	}
+	if defaults.SEO != nil {
+		seo := *defaults.SEO
+		seo.Keywords = append([]string(nil), defaults.SEO.Keywords...)
+		request.SEO = &seo
+	}
+	if defaults.Writing != nil {
+		writing := *defaults.Writing
+		request.Writing = &writing
+	}
+	return request
+}
+
+func generation(request *semanticpen.GenerateArticleRequest) *semanticpen.GenerationOptions {
+	if request.Generation == nil {
+		request.Generation = &semanticpen.GenerationOptions{}
+	}
+	return request.Generation
+}
+
+func writing(request *semanticpen.GenerateArticleRequest) *semanticpen.WritingOptions {
+	if request.Writing == nil {
+		request.Writing = &semanticpen.WritingOptions{}
+	}
+	return request.Writing
+}
+
+// normalize lowercases a column name and drops everything but letters and digits
+func normalize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+func isBlank(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,278 @@
+package bulk
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pushkarsingh32/semanticpen-go-sdk"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   Format
+		mapping  *Mapping
+		defaults *semanticpen.GenerateArticleRequest
+		input    string
+		want     []Row
+	}{
+		{
+			name:   "csv with default mapping",
+			format: FormatCSV,
+			input:  "\ufeffKeyword,Language,Project Name,Keywords,Tone\ncold brew,en,Coffee, \"beans, grinders\",casual\n",
+			want: []Row{{Line: 2, Request: semanticpen.GenerateArticleRequest{
+				TargetKeyword: "cold brew",
+				Generation:    &semanticpen.GenerationOptions{Language: "en", ProjectName: "Coffee"},
+				SEO:           &semanticpen.SEOOptions{Keywords: []string{"beans", "grinders"}},
+				Writing:       &semanticpen.WritingOptions{Tone: "casual"},
+			}}},
+		},
+		{
+			name:    "csv with custom mapping and separator",
+			format:  FormatCSV,
+			mapping: &Mapping{Keyword: "Target Keyword", Length: "words", Keywords: "tags", KeywordsSeparator: "|"},
+			input:   "target_keyword,WORDS,tags,ignored\nespresso,long,crema|shots,x\n\n,,,\nlatte,,,y\n",
+			want: []Row{
+				{Line: 2, Request: semanticpen.GenerateArticleRequest{
+					TargetKeyword: "espresso",
+					SEO:           &semanticpen.SEOOptions{Keywords: []string{"crema", "shots"}},
+					Writing:       &semanticpen.WritingOptions{Length: "long"},
+				}},
+				{Line: 5, Request: semanticpen.GenerateArticleRequest{TargetKeyword: "latte"}},
+			},
+		},
+		{
+			name:   "jsonl with keywords array",
+			format: FormatJSONL,
+			input:  `{"keyword": "pour over", "keywords": ["filters", " kettles "], "Country": "US"}` + "\n\n" + `{"Keyword": "aeropress", "keywords": "inverted, paper"}` + "\n",
+			want: []Row{
+				{Line: 1, Request: semanticpen.GenerateArticleRequest{
+					TargetKeyword: "pour over",
+					Generation:    &semanticpen.GenerationOptions{Country: "US"},
+					SEO:           &semanticpen.SEOOptions{Keywords: []string{"filters", "kettles"}},
+				}},
+				{Line: 3, Request: semanticpen.GenerateArticleRequest{
+					TargetKeyword: "aeropress",
+					SEO:           &semanticpen.SEOOptions{Keywords: []string{"inverted", "paper"}},
+				}},
+			},
+		},
+		{
+			name:   "lines",
+			format: FormatLines,
+			input:  "# keywords\ncold brew\n\n  espresso  \n",
+			want: []Row{
+				{Line: 2, Request: semanticpen.GenerateArticleRequest{TargetKeyword: "cold brew"}},
+				{Line: 4, Request: semanticpen.GenerateArticleRequest{TargetKeyword: "espresso"}},
+			},
+		},
+		{
+			name:   "defaults filled and overridden",
+			format: FormatCSV,
+			defaults: &semanticpen.GenerateArticleRequest{
+				Generation: &semanticpen.GenerationOptions{Language: "en", Country: "US"},
+				Writing:    &semanticpen.WritingOptions{Tone: "formal"},
+			},
+			input: "keyword,language\nmatcha,ja\n",
+			want: []Row{{Line: 2, Request: semanticpen.GenerateArticleRequest{
+				TargetKeyword: "matcha",
+				Generation:    &semanticpen.GenerationOptions{Language: "ja", Country: "US"},
+				Writing:       &semanticpen.WritingOptions{Tone: "formal"},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := Read(strings.NewReader(tt.input), &Options{Format: tt.format, Mapping: tt.mapping, Defaults: tt.defaults})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("Read() =\n%+v\nwant\n%+v", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadRejectsInvalidRows(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+		want   []string // Every RowError, in order
+	}{
+		{
+			name:   "missing keywords",
+			format: FormatCSV,
+			input:  "keyword,language\ncold brew,en\n,en\nlatte,fr\n,de\n",
+			want:   []string{"line 3: keyword: is required", "line 5: keyword: is required"},
+		},
+		{
+			name:   "too many columns",
+			format: FormatCSV,
+			input:  "keyword\ncold brew\nlatte,extra\n",
+			want:   []string{"line 3: has 2 columns, header has 1"},
+		},
+		{
+			name:   "invalid json and types",
+			format: FormatJSONL,
+			input:  `{"keyword": "ok"}` + "\n{not json}\n" + `{"keyword": {"a": 1}, "keywords": [1, 2]}` + "\n",
+			want: []string{
+				"line 2: invalid JSON: invalid character 'n' looking for beginning of object key string",
+				"line 3: keyword: must be a string",
+				"line 3: keyword: is required",
+				"line 3: keywords: must be a list of strings",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := Read(strings.NewReader(tt.input), &Options{Format: tt.format})
+			if rows != nil {
+				t.Errorf("Read() returned %d rows alongside invalid ones", len(rows))
+			}
+			var rowErrs RowErrors
+			if !errors.As(err, &rowErrs) {
+				t.Fatalf("Read() error = %v, want RowErrors", err)
+			}
+			var got []string
+			for _, e := range rowErrs {
+				got = append(got, e.Error())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RowErrors =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRowErrorsMessage(t *testing.T) {
+	one := RowErrors{{Line: 2, Field: "keyword", Message: "is required"}}
+	two := append(one, &RowError{Line: 4, Message: "bad"})
+	if one.Error() != "line 2: keyword: is required" {
+		t.Errorf("Error() = %q", one.Error())
+	}
+	if two.Error() != "2 invalid rows; first: line 2: keyword: is required" {
+		t.Errorf("Error() = %q", two.Error())
+	}
+}
+
+func TestReadEmptyInput(t *testing.T) {
+	for _, format := range []Format{FormatCSV, FormatJSONL, FormatLines} {
+		if _, err := Read(strings.NewReader(""), &Options{Format: format}); !errors.Is(err, ErrNoRows) {
+			t.Errorf("%s: Read() error = %v, want ErrNoRows", format, err)
+		}
+	}
+	if _, err := Read(strings.NewReader("keyword\n\n"), &Options{Format: FormatCSV}); !errors.Is(err, ErrNoRows) {
+		t.Errorf("header only: Read() error = %v, want ErrNoRows", err)
+	}
+	if _, err := Read(strings.NewReader("x"), nil); err == nil {
+		t.Error("Read() without a format succeeded")
+	}
+}
+
+func TestReadFileDetectsFormat(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"in.csv":    "keyword\ncold brew\n",
+		"in.ndjson": `{"keyword": "cold brew"}` + "\n",
+		"in.txt":    "cold brew\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		rows, err := ReadFile(path, nil)
+		if err != nil || len(rows) != 1 || rows[0].Request.TargetKeyword != "cold brew" {
+			t.Errorf("ReadFile(%s) = %+v, %v", name, rows, err)
+		}
+	}
+}
+
+func TestMappingSet(t *testing.T) {
+	m := DefaultMapping()
+	for field, column := range map[string]string{"targetKeyword": "Topic", "project-name": "Client", "SEO Keywords": "Tags"} {
+		if err := m.Set(field, column); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if m.Keyword != "Topic" || m.ProjectName != "Client" || m.Keywords != "Tags" {
+		t.Errorf("Mapping = %+v", m)
+	}
+	if err := m.Set("colour", "x"); err == nil {
+		t.Error("Set() accepted an unknown field")
+	}
+}
+
+func TestWriter(t *testing.T) {
+	row := Row{Line: 2, Request: semanticpen.GenerateArticleRequest{TargetKeyword: "cold brew"}}
+	results := []Result{
+		NewResult(row, semanticpen.BatchResult{
+			Status:     semanticpen.BatchSucceeded,
+			ArticleIDs: []string{"a1", "a2"},
+			Articles:   []*semanticpen.Article{{Status: semanticpen.StatusFinished, Title: "Cold Brew, Explained"}, nil},
+		}),
+		NewResult(Row{Line: 3, Request: semanticpen.GenerateArticleRequest{TargetKeyword: "latte"}},
+			semanticpen.BatchResult{Status: semanticpen.BatchFailed, Err: errors.New("quota exceeded")}),
+	}
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{
+			format: FormatCSV,
+			want: "line,keyword,status,article_ids,article_statuses,titles,error\n" +
+				"2,cold brew,succeeded,a1; a2,finished; ,\"Cold Brew, Explained; \",\n" +
+				"3,latte,failed,,,,quota exceeded\n",
+		},
+		{
+			format: FormatJSONL,
+			want: `{"line":2,"keyword":"cold brew","status":"succeeded","articleIds":["a1","a2"],"articleStatuses":["finished",""],"titles":["Cold Brew, Explained",""]}` + "\n" +
+				`{"line":3,"keyword":"latte","status":"failed","error":"quota exceeded"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, result := range results {
+				if err := w.Write(result); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterEmptyCSVHasHeader(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewWriter(&buf, FormatCSV)
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != strings.Join(csvHeader, ",")+"\n" {
+		t.Errorf("output = %q", buf.String())
+	}
+	if _, err := NewWriter(&buf, FormatLines); err == nil {
+		t.Error("NewWriter(FormatLines) succeeded")
+	}
+}
@@ -0,0 +1,116 @@
+package bulk
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pushkarsingh32/semanticpen-go-sdk"
+)
+
+// Result is the outcome of one input row
+type Result struct {
+	Line       int      `json:"line"`
+	Keyword    string   `json:"keyword"`
+	Status     string   `json:"status"` // The batch item status: succeeded, failed, timed_out or cancelled
+	ArticleIDs []string `json:"articleIds,omitempty"`
+	Statuses   []string `json:"articleStatuses,omitempty"` // Last seen status of each article, in ArticleIDs order
+	Titles     []string `json:"titles,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// NewResult combines an input row with its batch result
+func NewResult(row Row, result semanticpen.BatchResult) Result {
+	out := Result{
+		Line:       row.Line,
+		Keyword:    row.Request.TargetKeyword,
+		Status:     string(result.Status),
+		ArticleIDs: result.ArticleIDs,
+	}
+	for i := range result.ArticleIDs {
+		var article *semanticpen.Article
+		if i < len(result.Articles) {
+			article = result.Articles[i]
+		}
+		if article == nil {
+			out.Statuses = append(out.Statuses, "")
+			out.Titles = append(out.Titles, "")
+			continue
+		}
+		out.Statuses = append(out.Statuses, string(article.Status))
+		out.Titles = append(out.Titles, article.Title)
+	}
+	if result.Err != nil {
+		out.Error = result.Err.Error()
+	}
+	return out
+}
+
+// csvHeader is the first row written by a CSV Writer
+var csvHeader = []string{"line", "keyword", "status", "article_ids", "article_statuses", "titles", "error"}
+
+// Writer writes results as CSV or JSON lines. Multi-valued CSV cells are
+// joined with "; ".
+type Writer struct {
+	format      Format
+	w           io.Writer
+	csv         *csv.Writer
+	wroteHeader bool
+}
+
+// NewWriter creates a writer for FormatCSV or FormatJSONL
+func NewWriter(w io.Writer, format Format) (*Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &Writer{format: format, w: w, csv: csv.NewWriter(w)}, nil
+	case FormatJSONL:
+		return &Writer{format: format, w: w}, nil
+	}
+	return nil, fmt.Errorf("bulk: unsupported output format %q", format)
+}
+
+// Write writes one result
+func (w *Writer) Write(result Result) error {
+	if w.format == FormatJSONL {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w.w, "%s\n", data)
+		return err
+	}
+
+	if !w.wroteHeader {
+		if err := w.csv.Write(csvHeader); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+	return w.csv.Write([]string{
+		strconv.Itoa(result.Line),
+		result.Keyword,
+		result.Status,
+		strings.Join(result.ArticleIDs, "; "),
+		strings.Join(result.Statuses, "; "),
+		strings.Join(result.Titles, "; "),
+		result.Error,
+	})
+}
+
+// Flush writes any buffered data; call it once all results are written
+func (w *Writer) Flush() error {
+	if w.csv == nil {
+		return nil
+	}
+	if !w.wroteHeader {
+		if err := w.csv.Write(csvHeader); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
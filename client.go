@@ -2,6 +2,7 @@ package semanticpen
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,17 +17,32 @@ const (
 
 // Client represents the SemanticPen API client
 type Client struct {
-	apiKey     string
-	baseURL    string
-	httpClient *http.Client
-	debug      bool
+	apiKey        string
+	baseURL       string
+	httpClient    *http.Client
+	logger        Logger
+	logOptions    LogOptions
+	retry         *RetryPolicy
+	rateLimitWait *RateLimitWait
+	limiter       *rateLimiter
+	jobStore      JobStore
+	idempotency   *idempotencyCache
 }
 
 // Config holds configuration options for the client
 type Config struct {
-	BaseURL string
-	Timeout time.Duration
-	Debug   bool
+	BaseURL        string
+	Timeout        time.Duration
+	Debug          bool           // Log to stderr when no Logger is set, including request and response bodies
+	Logger         Logger         // Structured logger; *slog.Logger can be used directly
+	LogOptions     *LogOptions    // Body logging, truncation and redaction settings
+	Retry          *RetryPolicy   // Retry policy for idempotent methods, and keyed POSTs if it opts in; nil disables retries
+	RateLimitWait  *RateLimitWait // Sleep through short 429 Retry-After periods; nil returns *RateLimitError immediately
+	Limiter        *LimiterConfig // Client-side rate limits shared by all goroutines using the client
+	HTTPClient     *http.Client   // Base HTTP client (proxy, mTLS, ...); copied, never modified. Timeout applies if it has none
+	Middleware     []Middleware   // RoundTripper layers wrapped around HTTPClient's transport, outermost first
+	JobStore       JobStore       // Journal of submitted articles and their last-seen status, used by Resume
+	IdempotencyTTL time.Duration  // How long responses are cached per idempotency key; defaults to DefaultIdempotencyTTL
 }
 
 // NewClient creates a new SemanticPen client with the given API key and optional config
@@ -44,62 +60,141 @@ func NewClient(apiKey string, config *Config) *Client {
 	}
 
 	return &Client{
-		apiKey:  apiKey,
-		baseURL: config.BaseURL,
-		debug:   config.Debug,
-		httpClient: &http.Client{
-			Timeout: config.Timeout,
-		},
+		apiKey:        apiKey,
+		baseURL:       config.BaseURL,
+		logger:        newLogger(config),
+		logOptions:    newLogOptions(config),
+		retry:         config.Retry.withDefaults(),
+		rateLimitWait: config.RateLimitWait.withDefaults(),
+		limiter:       newRateLimiter(config.Limiter),
+		httpClient:    newHTTPClient(config),
+		jobStore:      config.JobStore,
+		idempotency:   newIdempotencyCache(config.IdempotencyTTL),
 	}
 }
 
-// makeRequest makes an HTTP request to the API
-func (c *Client) makeRequest(method, endpoint string, body interface{}) (*http.Response, error) {
-	url := c.baseURL + endpoint
-
-	var bodyReader io.Reader
+// makeRequest makes an HTTP request to the API, bound to the given context.
+// Idempotent requests are retried according to the client's retry policy, as
+// are requests carrying an Idempotency-Key header when the policy sets
+// RetryIdempotentPosts. Extra headers may be nil.
+func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body interface{}, header http.Header) (*http.Response, error) {
+	var payload []byte
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		bodyReader = bytes.NewBuffer(jsonBody)
+		payload = jsonBody
+		c.logBody("semanticpen request body", endpoint, payload)
+	}
+
+	maxRetries := 0
+	if c.retry != nil && (isIdempotent(method) || c.retry.retriesKeyed(header)) {
+		maxRetries = c.retry.MaxRetries
+	}
+
+	rateLimitWaits := 0
+	for attempt := 0; ; {
+		if err := c.limiter.wait(ctx, method, endpoint); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.doRequest(ctx, method, endpoint, payload, header, attempt+rateLimitWaits+1)
+
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests &&
+			c.rateLimitWait != nil && rateLimitWaits < c.rateLimitWait.MaxRetries {
+			if delay, ok := c.rateLimitWait.rateLimitDelay(resp); ok {
+				rateLimitWaits++
+				resp.Body.Close()
+
+				c.logger.Warn("semanticpen rate limited, waiting",
+					"method", method, "endpoint", endpoint, "delay", delay, "wait", rateLimitWaits)
+
+				if err := sleepContext(ctx, delay); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+
+		if attempt >= maxRetries || ctx.Err() != nil || !c.retry.shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		event := RetryEvent{
+			Method:   method,
+			Endpoint: endpoint,
+			Attempt:  attempt + 1,
+			Err:      err,
+			Delay:    c.retry.backoff(attempt + 1),
+		}
+		if resp != nil {
+			event.StatusCode = resp.StatusCode
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
 
-		if c.debug {
-			fmt.Printf("[DEBUG] Request body: %s\n", string(jsonBody))
+		if c.retry.OnRetry != nil {
+			c.retry.OnRetry(event)
 		}
+		c.logger.Warn("semanticpen retrying request",
+			"method", method, "endpoint", endpoint, "attempt", event.Attempt,
+			"status", event.StatusCode, "delay", event.Delay, "error", err)
+
+		if err := sleepContext(ctx, event.Delay); err != nil {
+			return nil, err
+		}
+		attempt++
 	}
+}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+// doRequest performs a single HTTP round trip
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, payload []byte, header http.Header, attempt int) (*http.Response, error) {
+	var bodyReader io.Reader
+	if payload != nil {
+		bodyReader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+endpoint, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	for key, values := range header {
+		req.Header[key] = values
+	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
 
-	if c.debug {
-		fmt.Printf("[DEBUG] %s %s\n", method, url)
-		for k, v := range req.Header {
-			if k != "Authorization" {
-				fmt.Printf("[DEBUG] %s: %s\n", k, v[0])
-			} else {
-				fmt.Printf("[DEBUG] %s: Bearer ***\n", k)
-			}
-		}
-	}
-
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
+	latency := time.Since(start)
 	if err != nil {
+		c.logger.Warn("semanticpen request failed",
+			"method", method, "endpoint", endpoint, "latency", latency, "attempt", attempt, "error", err)
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
+	c.logger.Debug("semanticpen request",
+		"method", method, "endpoint", endpoint, "status", resp.StatusCode, "latency", latency, "attempt", attempt)
+
 	return resp, nil
 }
 
+// RateLimitQueueDepth returns the number of requests currently blocked by the
+// client-side rate limiter, for monitoring
+func (c *Client) RateLimitQueueDepth() int {
+	return c.limiter.queueDepth()
+}
+
 // TestConnection tests the connection by making a simple API call
 func (c *Client) TestConnection() error {
+	return c.TestConnectionWithContext(context.Background())
+}
+
+// TestConnectionWithContext is like TestConnection but honors ctx cancellation
+func (c *Client) TestConnectionWithContext(ctx context.Context) error {
 	// Test connection by trying to generate a simple article
-	_, err := c.GenerateArticle("Connection test", nil)
+	_, err := c.GenerateArticleWithContext(ctx, "Connection test", nil)
 	return err
-}
\ No newline at end of file
+}
@@ -0,0 +1,200 @@
+package semanticpen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// statusServer replies with statuses in order, repeating the last one, and
+// counts the requests it receives
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&requests, 1))
+		if n > len(statuses) {
+			n = len(statuses)
+		}
+		w.WriteHeader(statuses[n-1])
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestMakeRequestRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		idempotent   bool // Send an Idempotency-Key header
+		keyedPosts   bool // Set RetryIdempotentPosts
+		codes        []int
+		statuses     []int
+		wantStatus   int
+		wantRequests int32
+	}{
+		{name: "retries listed status", method: http.MethodGet, statuses: []int{503, 200}, wantStatus: 200, wantRequests: 2},
+		{name: "retries every default status", method: http.MethodGet, statuses: []int{408, 502, 504, 200}, wantStatus: 200, wantRequests: 4},
+		{name: "gives up after max retries", method: http.MethodGet, statuses: []int{503}, wantStatus: 503, wantRequests: 4},
+		{name: "does not retry unlisted status", method: http.MethodGet, statuses: []int{500, 200}, wantStatus: 500, wantRequests: 1},
+		{name: "does not retry client error", method: http.MethodGet, statuses: []int{400, 200}, wantStatus: 400, wantRequests: 1},
+		{name: "custom status codes", method: http.MethodGet, codes: []int{500}, statuses: []int{500, 200}, wantStatus: 200, wantRequests: 2},
+		{name: "custom codes replace defaults", method: http.MethodGet, codes: []int{500}, statuses: []int{503, 200}, wantStatus: 503, wantRequests: 1},
+		{name: "retries DELETE", method: http.MethodDelete, statuses: []int{502, 204}, wantStatus: 204, wantRequests: 2},
+		{name: "does not retry POST", method: http.MethodPost, statuses: []int{503, 200}, wantStatus: 503, wantRequests: 1},
+		{name: "does not retry keyed POST by default", method: http.MethodPost, idempotent: true, statuses: []int{503, 200}, wantStatus: 503, wantRequests: 1},
+		{name: "retries keyed POST when enabled", method: http.MethodPost, idempotent: true, keyedPosts: true, statuses: []int{503, 200}, wantStatus: 200, wantRequests: 2},
+		{name: "does not retry unkeyed POST when enabled", method: http.MethodPost, keyedPosts: true, statuses: []int{503, 200}, wantStatus: 503, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := statusServer(t, tt.statuses...)
+
+			var events []RetryEvent
+			client := NewClient("key", &Config{
+				BaseURL: server.URL,
+				Retry: &RetryPolicy{
+					MaxRetries:           3,
+					BaseDelay:            time.Millisecond,
+					RetryableStatusCodes: tt.codes,
+					RetryIdempotentPosts: tt.keyedPosts,
+					OnRetry:              func(event RetryEvent) { events = append(events, event) },
+				},
+			})
+
+			var header http.Header
+			if tt.idempotent {
+				header = http.Header{IdempotencyKeyHeader: []string{"key-1"}}
+			}
+			resp, err := client.makeRequest(context.Background(), tt.method, "/api/test", nil, header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus || *requests != tt.wantRequests {
+				t.Errorf("got status %d after %d requests, want %d after %d", resp.StatusCode, *requests, tt.wantStatus, tt.wantRequests)
+			}
+			if len(events) != int(tt.wantRequests)-1 {
+				t.Fatalf("OnRetry called %d times, want %d", len(events), tt.wantRequests-1)
+			}
+			for i, event := range events {
+				status := tt.statuses[len(tt.statuses)-1]
+				if i < len(tt.statuses) {
+					status = tt.statuses[i]
+				}
+				if event.Attempt != i+1 || event.StatusCode != status || event.Method != tt.method || event.Endpoint != "/api/test" {
+					t.Errorf("event %d = %+v", i, event)
+				}
+			}
+		})
+	}
+}
+
+func TestMakeRequestWithoutRetryPolicy(t *testing.T) {
+	server, requests := statusServer(t, 503, 200)
+	client := NewClient("key", &Config{BaseURL: server.URL})
+
+	resp, err := client.makeRequest(context.Background(), http.MethodGet, "/api/test", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 503 || *requests != 1 {
+		t.Errorf("got status %d after %d requests, want 503 after 1", resp.StatusCode, *requests)
+	}
+}
+
+func TestMakeRequestRetriesNetworkErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	for _, retry := range []bool{true, false} {
+		var retries int
+		client := NewClient("key", &Config{
+			BaseURL: server.URL,
+			Retry: &RetryPolicy{
+				MaxRetries:          2,
+				BaseDelay:           time.Millisecond,
+				RetryOnNetworkError: retry,
+				OnRetry: func(event RetryEvent) {
+					if event.Err == nil || event.StatusCode != 0 {
+						t.Errorf("event = %+v, want a network error", event)
+					}
+					retries++
+				},
+			},
+		})
+
+		if _, err := client.makeRequest(context.Background(), http.MethodGet, "/api/test", nil, nil); err == nil {
+			t.Fatal("makeRequest() succeeded against a closed server")
+		}
+		if want := map[bool]int{true: 2, false: 0}[retry]; retries != want {
+			t.Errorf("RetryOnNetworkError=%v: %d retries, want %d", retry, retries, want)
+		}
+	}
+}
+
+func TestMakeRequestCancelledDuringBackoff(t *testing.T) {
+	server, requests := statusServer(t, 503, 200)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := NewClient("key", &Config{
+		BaseURL: server.URL,
+		Retry: &RetryPolicy{
+			MaxRetries: 3,
+			BaseDelay:  time.Hour,
+			OnRetry:    func(RetryEvent) { cancel() },
+		},
+	})
+
+	start := time.Now()
+	resp, err := client.makeRequest(ctx, http.MethodGet, "/api/test", nil, nil)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("makeRequest() error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("makeRequest() took %v to notice cancellation", elapsed)
+	}
+	if *requests != 1 {
+		t.Errorf("%d requests, want 1", *requests)
+	}
+}
+
+func TestGenerateArticleRetryOptIn(t *testing.T) {
+	for _, keyedPosts := range []bool{false, true} {
+		var requests int32
+		var keys []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+			if atomic.AddInt32(&requests, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"articleId": "a1"}`))
+		}))
+
+		policy := DefaultRetryPolicy()
+		policy.BaseDelay = time.Millisecond
+		policy.RetryIdempotentPosts = keyedPosts
+		client := NewClient("key", &Config{BaseURL: server.URL, Retry: policy})
+		_, err := client.GenerateArticle("cold brew", nil)
+		server.Close()
+
+		switch {
+		case !keyedPosts && (err == nil || requests != 1 || keys[0] != ""):
+			t.Errorf("without opt-in: err = %v, %d requests, keys %q; want one unkeyed request", err, requests, keys)
+		case keyedPosts && (err != nil || requests != 2 || keys[0] == "" || keys[0] != keys[1]):
+			t.Errorf("with opt-in: err = %v, %d requests, keys %q; want a retry with the same key", err, requests, keys)
+		}
+	}
+}
@@ -0,0 +1,517 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pushkarsingh32/semanticpen-go-sdk"
+	"github.com/pushkarsingh32/semanticpen-go-sdk/bulk"
+)
+
+// requestFlags map onto GenerateArticleRequest
+type requestFlags struct {
+	projectName    string
+	language       string
+	country        string
+	perspective    string
+	purpose        string
+	clickbaitLevel int
+
+	seoTitle       string
+	seoDescription string
+	keywords       string
+	useSchema      bool
+
+	style         string
+	tone          string
+	length        string
+	includeImages bool
+	imageStyle    string
+
+	webhookURL     string
+	idempotencyKey string
+}
+
+func (r *requestFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&r.projectName, "project-name", "", "project name")
+	fs.StringVar(&r.language, "language", "", "article language")
+	fs.StringVar(&r.country, "country", "", "target country")
+	fs.StringVar(&r.perspective, "perspective", "", "narrative perspective")
+	fs.StringVar(&r.purpose, "purpose", "", "article purpose")
+	fs.IntVar(&r.clickbaitLevel, "clickbait-level", 0, "clickbait level")
+
+	fs.StringVar(&r.seoTitle, "seo-title", "", "SEO title")
+	fs.StringVar(&r.seoDescription, "seo-description", "", "SEO meta description")
+	fs.StringVar(&r.keywords, "keywords", "", "comma-separated SEO keywords")
+	fs.BoolVar(&r.useSchema, "use-schema", false, "request schema.org markup")
+
+	fs.StringVar(&r.style, "style", "", "writing style")
+	fs.StringVar(&r.tone, "tone", "", "writing tone")
+	fs.StringVar(&r.length, "length", "", "article length")
+	fs.BoolVar(&r.includeImages, "include-images", false, "include images")
+	fs.StringVar(&r.imageStyle, "image-style", "", "image style")
+
+	fs.StringVar(&r.webhookURL, "webhook-url", "", "URL notified when generation ends")
+	fs.StringVar(&r.idempotencyKey, "idempotency-key", "", "idempotency key for the generation request")
+}
+
+// request builds the request options, leaving groups with no flags set nil
+func (r *requestFlags) request() *semanticpen.GenerateArticleRequest {
+	request := &semanticpen.GenerateArticleRequest{
+		WebhookURL:     r.webhookURL,
+		IdempotencyKey: r.idempotencyKey,
+	}
+
+	generation := semanticpen.GenerationOptions{
+		ProjectName:    r.projectName,
+		Language:       r.language,
+		Country:        r.country,
+		Perspective:    r.perspective,
+		Purpose:        r.purpose,
+		ClickbaitLevel: r.clickbaitLevel,
+	}
+	if generation != (semanticpen.GenerationOptions{}) {
+		request.Generation = &generation
+	}
+
+	seo := semanticpen.SEOOptions{
+		Title:       r.seoTitle,
+		Description: r.seoDescription,
+		Keywords:    splitList(r.keywords),
+		UseSchema:   r.useSchema,
+	}
+	if seo.Title != "" || seo.Description != "" || len(seo.Keywords) > 0 || seo.UseSchema {
+		request.SEO = &seo
+	}
+
+	writing := semanticpen.WritingOptions{
+		Style:         r.style,
+		Tone:          r.tone,
+		Length:        r.length,
+		IncludeImages: r.includeImages,
+		ImageStyle:    r.imageStyle,
+	}
+	if writing != (semanticpen.WritingOptions{}) {
+		request.Writing = &writing
+	}
+
+	return request
+}
+
+// waitFlags map onto GenerateAndWaitOptions
+type waitFlags struct {
+	interval    time.Duration
+	timeout     time.Duration
+	concurrency int
+}
+
+func (w *waitFlags) register(fs *flag.FlagSet) {
+	fs.DurationVar(&w.interval, "interval", semanticpen.DefaultWaitInterval, "delay between status checks")
+	fs.DurationVar(&w.timeout, "timeout", 10*time.Minute, "give up waiting after this long")
+	fs.IntVar(&w.concurrency, "concurrency", semanticpen.DefaultWaitConcurrency, "articles processed at the same time")
+}
+
+func (w *waitFlags) options() *semanticpen.GenerateAndWaitOptions {
+	return &semanticpen.GenerateAndWaitOptions{Interval: w.interval, Timeout: w.timeout}
+}
+
+func runGenerate(ctx context.Context, args []string, stdout io.Writer) error {
+	var g globalFlags
+	var r requestFlags
+	var w waitFlags
+	var wait bool
+	fs := newFlagSet("generate", "<keyword>", &g)
+	r.register(fs)
+	w.register(fs)
+	fs.BoolVar(&wait, "wait", false, "wait for the articles to finish and print them")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("%w: a target keyword is required", errUsage)
+	}
+	if !wait {
+		if err := g.requireFormat("generate without --wait", formatTable, formatJSON); err != nil {
+			return err
+		}
+	}
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+
+	keyword := strings.Join(positional, " ")
+	out := &printer{w: stdout, format: g.output}
+	if !wait {
+		response, err := client.GenerateArticleWithContext(ctx, keyword, r.request())
+		if err != nil {
+			return err
+		}
+		return out.generated(response)
+	}
+
+	results, err := client.GenerateArticlesAndWait(ctx, keyword, r.request(), &semanticpen.WaitForArticlesOptions{
+		Concurrency: w.concurrency,
+		Wait:        w.options(),
+	})
+	if printErr := out.results(results); printErr != nil {
+		return printErr
+	}
+	return err
+}
+
+func runGet(ctx context.Context, args []string, stdout io.Writer) error {
+	var g globalFlags
+	fs := newFlagSet("get", "<id>...", &g)
+
+	ids, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: at least one article ID is required", errUsage)
+	}
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+
+	var articles []*semanticpen.Article
+	for _, id := range ids {
+		article, err := client.GetArticleWithContext(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+		articles = append(articles, article)
+	}
+	return (&printer{w: stdout, format: g.output}).articles(articles)
+}
+
+func runWait(ctx context.Context, args []string, stdout io.Writer) error {
+	var g globalFlags
+	var w waitFlags
+	fs := newFlagSet("wait", "<id>...", &g)
+	w.register(fs)
+
+	ids, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: at least one article ID is required", errUsage)
+	}
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+
+	results, err := client.WaitForArticles(ctx, ids, &semanticpen.WaitForArticlesOptions{
+		Concurrency: w.concurrency,
+		Wait:        w.options(),
+	})
+	if printErr := (&printer{w: stdout, format: g.output}).results(results); printErr != nil {
+		return printErr
+	}
+	return err
+}
+
+func runDelete(ctx context.Context, args []string, stdout io.Writer) error {
+	var g globalFlags
+	fs := newFlagSet("delete", "<id>...", &g)
+
+	ids, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: at least one article ID is required", errUsage)
+	}
+	if err := g.requireFormat("delete", formatTable, formatJSON); err != nil {
+		return err
+	}
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+
+	out := &printer{w: stdout, format: g.output}
+	for _, id := range ids {
+		if err := client.DeleteArticleWithContext(ctx, id); err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+		if err := out.deleted(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runList(ctx context.Context, args []string, stdout io.Writer) error {
+	var g globalFlags
+	var projectID, status, keyword, cursor, after, before string
+	var limit, offset int
+	var all bool
+	fs := newFlagSet("list", "", &g)
+	fs.StringVar(&projectID, "project", "", "only articles in this project")
+	fs.StringVar(&status, "status", "", "only articles with this status: pending, processing, finished or failed")
+	fs.StringVar(&keyword, "keyword", "", "only articles matching this keyword")
+	fs.StringVar(&after, "created-after", "", "only articles created after this date (YYYY-MM-DD or RFC 3339)")
+	fs.StringVar(&before, "created-before", "", "only articles created before this date (YYYY-MM-DD or RFC 3339)")
+	fs.IntVar(&limit, "limit", 0, "page size")
+	fs.StringVar(&cursor, "cursor", "", "cursor returned by a previous page")
+	fs.IntVar(&offset, "offset", 0, "offset of the first article")
+	fs.BoolVar(&all, "all", false, "follow pagination and list every matching article")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return fmt.Errorf("%w: unexpected argument %q", errUsage, positional[0])
+	}
+	if err := g.requireFormat("list", formatTable, formatJSON); err != nil {
+		return err
+	}
+
+	options := &semanticpen.ListArticlesOptions{
+		ProjectID: projectID,
+		Status:    semanticpen.ArticleStatus(status),
+		Keyword:   keyword,
+		Limit:     limit,
+		Cursor:    cursor,
+		Offset:    offset,
+	}
+	if options.CreatedAfter, err = parseTime(after); err != nil {
+		return fmt.Errorf("%w: --created-after: %v", errUsage, err)
+	}
+	if options.CreatedBefore, err = parseTime(before); err != nil {
+		return fmt.Errorf("%w: --created-before: %v", errUsage, err)
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	out := &printer{w: stdout, format: g.output}
+
+	if !all {
+		list, err := client.ListArticlesWithContext(ctx, options)
+		if err != nil {
+			return err
+		}
+		if list.NextCursor != "" {
+			fmt.Fprintf(os.Stderr, "next cursor: %s\n", list.NextCursor)
+		}
+		return out.list(list.Articles)
+	}
+
+	var articles []semanticpen.Article
+	it := client.IterateArticles(options)
+	for it.Next(ctx) {
+		articles = append(articles, *it.Article())
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	return out.list(articles)
+}
+
+func runBatch(ctx context.Context, args []string, stdout io.Writer) error {
+	var g globalFlags
+	var r requestFlags
+	var w waitFlags
+	var rate float64
+	var inputFormat, resultsPath, resultsFormat, separator string
+	mapping := bulk.DefaultMapping()
+	fs := newFlagSet("batch", "<file>", &g)
+	r.register(fs)
+	w.register(fs)
+	fs.Float64Var(&rate, "rate", 0, "maximum generation requests per second (0 for no limit)")
+	fs.StringVar(&inputFormat, "format", "", "input format: csv, jsonl or lines (default from the file extension; lines for stdin)")
+	fs.Var(mappingFlag{mapping}, "map", "map a field to an input column, e.g. keyword=\"Target Keyword\" (repeatable)")
+	fs.StringVar(&separator, "keywords-separator", ",", "separator for the keywords column in CSV input")
+	fs.StringVar(&resultsPath, "results", "", "write per-row results to this CSV or JSONL file")
+	fs.StringVar(&resultsFormat, "results-format", "", "results format: csv or jsonl (default from the --results extension)")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("%w: exactly one input file is required", errUsage)
+	}
+	if r.idempotencyKey != "" {
+		return fmt.Errorf("%w: --idempotency-key cannot be shared by a batch", errUsage)
+	}
+	if err := g.requireFormat("batch", formatTable, formatJSON); err != nil {
+		return err
+	}
+	mapping.KeywordsSeparator = separator
+
+	options := &bulk.Options{Mapping: mapping, Defaults: r.request()}
+	if inputFormat != "" {
+		if options.Format, err = bulk.ParseFormat(inputFormat); err != nil {
+			return fmt.Errorf("%w: --format: %v", errUsage, err)
+		}
+	}
+	rows, err := readRows(positional[0], options)
+	if err != nil {
+		return err
+	}
+
+	// Build the client first so a configuration error does not truncate an
+	// existing results file
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+
+	var results *bulk.Writer
+	if resultsPath != "" {
+		file, writer, err := createResults(resultsPath, resultsFormat)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		results = writer
+	}
+
+	requests := make([]semanticpen.GenerateArticleRequest, len(rows))
+	for i, row := range rows {
+		requests[i] = row.Request
+	}
+
+	batchOptions := &semanticpen.BatchOptions{Concurrency: w.concurrency, Wait: w.options()}
+	if rate > 0 {
+		batchOptions.SubmitRate = &semanticpen.RateLimit{RequestsPerSecond: rate, Burst: 1}
+	}
+
+	batch := semanticpen.NewBatch(client, batchOptions)
+	stream, err := batch.Run(ctx, requests)
+	if err != nil {
+		return err
+	}
+
+	out := &printer{w: stdout, format: g.output}
+	err = out.batch(stream, func(result semanticpen.BatchResult) error {
+		if results == nil {
+			return nil
+		}
+		return results.Write(bulk.NewResult(rows[result.Index], result))
+	})
+	if err == nil && results != nil {
+		err = results.Flush()
+	}
+	if err != nil {
+		batch.Cancel()
+		for range stream {
+		}
+		return err
+	}
+
+	summary := batch.Summary()
+	fmt.Fprintf(os.Stderr, "%d succeeded, %d failed, %d timed out, %d cancelled in %v\n",
+		len(summary.Succeeded), len(summary.Failed), len(summary.TimedOut), len(summary.Cancelled),
+		summary.Duration.Round(time.Second))
+	if len(summary.Succeeded) < summary.Total {
+		return fmt.Errorf("%d of %d articles did not finish", summary.Total-len(summary.Succeeded), summary.Total)
+	}
+	return nil
+}
+
+// readRows reads and validates the batch input, or stdin when path is "-".
+// Every invalid row is reported before returning, and nothing is submitted.
+func readRows(path string, options *bulk.Options) ([]bulk.Row, error) {
+	var rows []bulk.Row
+	var err error
+	if path == "-" {
+		if options.Format == "" {
+			options.Format = bulk.FormatLines
+		}
+		rows, err = bulk.Read(os.Stdin, options)
+	} else {
+		rows, err = bulk.ReadFile(path, options)
+	}
+
+	var rowErrs bulk.RowErrors
+	switch {
+	case errors.As(err, &rowErrs):
+		for _, rowErr := range rowErrs {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, rowErr)
+		}
+		return nil, fmt.Errorf("%w: %d invalid rows in %s, nothing was submitted", errUsage, len(rowErrs), path)
+	case errors.Is(err, bulk.ErrNoRows):
+		return nil, fmt.Errorf("%w: %s contains no keywords", errUsage, path)
+	case err != nil:
+		return nil, err
+	}
+	return rows, nil
+}
+
+// createResults opens the results file, picking the format from its extension when not given
+func createResults(path, format string) (*os.File, *bulk.Writer, error) {
+	f := bulk.DetectFormat(path)
+	if format != "" {
+		var err error
+		if f, err = bulk.ParseFormat(format); err != nil {
+			return nil, nil, fmt.Errorf("%w: --results-format: %v", errUsage, err)
+		}
+	}
+	if f != bulk.FormatCSV && f != bulk.FormatJSONL {
+		return nil, nil, fmt.Errorf("%w: --results must be a .csv or .jsonl file, or set --results-format", errUsage)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	writer, err := bulk.NewWriter(file, f)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return file, writer, nil
+}
+
+// mappingFlag parses repeated field=column pairs into a bulk.Mapping
+type mappingFlag struct {
+	mapping *bulk.Mapping
+}
+
+func (m mappingFlag) String() string { return "" }
+
+func (m mappingFlag) Set(value string) error {
+	field, column, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("expected field=column, got %q", value)
+	}
+	return m.mapping.Set(strings.TrimSpace(field), strings.TrimSpace(column))
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,198 @@
+// Command semanticpen generates and manages SemanticPen articles from the
+// command line.
+//
+// Usage:
+//
+//	semanticpen <command> [flags] [arguments]
+//
+// Commands:
+//
+//	generate  Generate an article for a keyword
+//	get       Fetch articles by ID
+//	wait      Wait for articles to finish
+//	delete    Delete articles by ID
+//	list      List articles
+//	batch     Generate articles for a list of keywords
+//
+// Settings are read from flags, then SEMANTICPEN_API_KEY, SEMANTICPEN_BASE_URL
+// and SEMANTICPEN_PROFILE, then the selected profile of the config file
+// (~/.config/semanticpen/config by default).
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/pushkarsingh32/semanticpen-go-sdk"
+)
+
+const usage = `Usage: semanticpen <command> [flags] [arguments]
+
+Commands:
+  generate <keyword>   Generate an article
+  get <id>...          Fetch articles
+  wait <id>...         Wait for articles to finish
+  delete <id>...       Delete articles
+  list                 List articles
+  batch <file>         Generate one article per keyword in file ("-" for stdin)
+
+Run "semanticpen <command> -h" for the flags of a command.
+`
+
+// errUsage marks errors caused by bad command-line arguments
+var errUsage = errors.New("usage error")
+
+type command struct {
+	name string
+	run  func(ctx context.Context, args []string, stdout io.Writer) error
+}
+
+var commands = []command{
+	{"generate", runGenerate},
+	{"get", runGet},
+	{"wait", runWait},
+	{"delete", runDelete},
+	{"list", runList},
+	{"batch", runBatch},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(stderr, usage)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(ctx, args[1:], stdout)
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			fmt.Fprintf(stderr, "semanticpen %s: %v\n", cmd.name, err)
+			return 2
+		default:
+			fmt.Fprintf(stderr, "semanticpen %s: %v\n", cmd.name, err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(stderr, "semanticpen: unknown command %q\n\n%s", args[0], usage)
+	return 2
+}
+
+// globalFlags are accepted by every command
+type globalFlags struct {
+	apiKey     string
+	baseURL    string
+	configPath string
+	profile    string
+	output     string
+	timeout    time.Duration
+	debug      bool
+}
+
+func (g *globalFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.apiKey, "api-key", "", "API key (default $SEMANTICPEN_API_KEY or the config file)")
+	fs.StringVar(&g.baseURL, "base-url", "", "API base URL (default $SEMANTICPEN_BASE_URL or "+semanticpen.DefaultBaseURL+")")
+	fs.StringVar(&g.configPath, "config", "", "config file (default $SEMANTICPEN_CONFIG or ~/.config/semanticpen/config)")
+	fs.StringVar(&g.profile, "profile", "", "config profile (default $SEMANTICPEN_PROFILE or the file's default profile)")
+	fs.StringVar(&g.output, "output", "table", "output format: table, json, html or markdown")
+	fs.StringVar(&g.output, "o", "table", "shorthand for --output")
+	fs.DurationVar(&g.timeout, "http-timeout", 0, "timeout for each HTTP request (default the profile's timeout or 30s)")
+	fs.BoolVar(&g.debug, "debug", false, "log requests to stderr")
+}
+
+// requireFormat rejects output formats a command cannot produce before any
+// request is made
+func (g *globalFlags) requireFormat(command string, formats ...string) error {
+	for _, format := range formats {
+		if g.output == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: output format %q is not supported for %s", errUsage, g.output, command)
+}
+
+// client builds a client from the flags, environment and config profile, in
+// that order of precedence
+func (g *globalFlags) client() (*semanticpen.Client, error) {
+	switch g.output {
+	case formatTable, formatJSON, formatHTML, formatMarkdown:
+	default:
+		return nil, fmt.Errorf("%w: unknown output format %q", errUsage, g.output)
+	}
+
+	client, err := semanticpen.NewClientFromConfig(&semanticpen.LoadOptions{
+		Path:    g.configPath,
+		Profile: g.profile,
+		APIKey:  g.apiKey,
+		BaseURL: g.baseURL,
+		Config: &semanticpen.Config{
+			Timeout:       g.timeout,
+			Debug:         g.debug,
+			Retry:         semanticpen.DefaultRetryPolicy(),
+			RateLimitWait: &semanticpen.RateLimitWait{MaxWait: time.Minute},
+		},
+	})
+	var validationErr *semanticpen.ValidationError
+	if errors.As(err, &validationErr) {
+		return nil, fmt.Errorf("%w: %v", errUsage, err)
+	}
+	return client, err
+}
+
+// parseArgs parses flags that may appear before, between or after positional
+// arguments and returns the positional arguments
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		// flag.Parse consumes a "--" terminator; everything after it is positional
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// newFlagSet creates a flag set for a command with the global flags registered
+func newFlagSet(name, args string, g *globalFlags) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: semanticpen %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	g.register(fs)
+	return fs
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		positional []string
+		output     string
+		err        error
+	}{
+		{name: "flags first", args: []string{"-o", "json", "a1"}, positional: []string{"a1"}, output: "json"},
+		{name: "flags after positional", args: []string{"a1", "a2", "--output", "json"}, positional: []string{"a1", "a2"}, output: "json"},
+		{name: "flags between positional", args: []string{"a1", "-o=json", "a2"}, positional: []string{"a1", "a2"}, output: "json"},
+		{name: "terminator", args: []string{"--", "a", "-b"}, positional: []string{"a", "-b"}, output: "table"},
+		{name: "terminator after positional", args: []string{"a", "-o", "json", "--", "-b", "--c"}, positional: []string{"a", "-b", "--c"}, output: "json"},
+		{name: "only terminator", args: []string{"--"}, output: "table"},
+		{name: "unknown flag", args: []string{"a", "-b"}, err: errUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &globalFlags{}
+			fs := newFlagSet("get", "<article-id>", g)
+			fs.SetOutput(io.Discard)
+
+			positional, err := parseArgs(fs, tt.args)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("parseArgs() error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(positional, tt.positional) || g.output != tt.output {
+				t.Errorf("parseArgs() = %q with output %q, want %q with %q", positional, g.output, tt.positional, tt.output)
+			}
+		})
+	}
+}
+
+func TestBatchConfigErrorKeepsResults(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "keywords.txt")
+	results := filepath.Join(dir, "results.csv")
+	if err := os.WriteFile(input, []byte("cold brew\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(results, []byte("previous results\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	args := []string{"batch", "--config", filepath.Join(dir, "missing.conf"), "--results", results, input}
+	if code := run(context.Background(), args, io.Discard, io.Discard); code == 0 {
+		t.Fatal("run() succeeded with a missing config file")
+	}
+	if data, _ := os.ReadFile(results); string(data) != "previous results\n" {
+		t.Errorf("results file = %q, want it untouched", data)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pushkarsingh32/semanticpen-go-sdk"
+)
+
+const (
+	formatTable    = "table"
+	formatJSON     = "json"
+	formatHTML     = "html"
+	formatMarkdown = "markdown"
+)
+
+// printer writes command results in the selected output format
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// generated prints the IDs returned by a generation request
+func (p *printer) generated(response *semanticpen.GenerateArticleResponse) error {
+	switch p.format {
+	case formatJSON:
+		return p.json(response)
+	case formatTable:
+		return p.table([]string{"ARTICLE ID", "PROJECT ID"}, func(row func(...string)) {
+			for _, id := range response.GetArticleIDs() {
+				row(id, response.ProjectID)
+			}
+		})
+	}
+	return p.unsupported("generate without --wait")
+}
+
+// articles prints full articles; JSON output is an object for a single
+// article and an array otherwise
+func (p *printer) articles(articles []*semanticpen.Article) error {
+	switch p.format {
+	case formatJSON:
+		if len(articles) == 1 {
+			return p.json(articles[0])
+		}
+		return p.json(articles)
+	case formatHTML:
+		for _, article := range articles {
+			if _, err := fmt.Fprintln(p.w, article.ArticleHTML); err != nil {
+				return err
+			}
+		}
+		return nil
+	case formatMarkdown:
+		for i, article := range articles {
+			md, err := article.Markdown(nil)
+			if err != nil {
+				return fmt.Errorf("%s: %w", article.ID, err)
+			}
+			if i > 0 {
+				md = "\n---\n\n" + md
+			}
+			if _, err := io.WriteString(p.w, md); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return p.table([]string{"ID", "STATUS", "PROGRESS", "TITLE"}, func(row func(...string)) {
+		for _, article := range articles {
+			row(article.ID, string(article.Status), strconv.Itoa(article.Progress)+"%", article.Title)
+		}
+	})
+}
+
+// results prints the finished articles of a multi-article wait, reporting
+// failures on their own rows in table and JSON output
+func (p *printer) results(results []semanticpen.ArticleResult) error {
+	switch p.format {
+	case formatJSON:
+		type result struct {
+			ArticleID string               `json:"articleId"`
+			Article   *semanticpen.Article `json:"article,omitempty"`
+			Error     string               `json:"error,omitempty"`
+		}
+		out := make([]result, len(results))
+		for i, r := range results {
+			out[i] = result{ArticleID: r.ArticleID, Article: r.Article, Error: errorString(r.Err)}
+		}
+		return p.json(out)
+	case formatHTML, formatMarkdown:
+		var articles []*semanticpen.Article
+		for _, r := range results {
+			if r.Article != nil {
+				articles = append(articles, r.Article)
+			}
+		}
+		return p.articles(articles)
+	}
+
+	return p.table([]string{"ID", "STATUS", "PROGRESS", "TITLE", "ERROR"}, func(row func(...string)) {
+		for _, r := range results {
+			if r.Article == nil {
+				row(r.ArticleID, "", "", "", errorString(r.Err))
+				continue
+			}
+			row(r.ArticleID, string(r.Article.Status), strconv.Itoa(r.Article.Progress)+"%", r.Article.Title, errorString(r.Err))
+		}
+	})
+}
+
+// list prints a page of articles without their content
+func (p *printer) list(articles []semanticpen.Article) error {
+	switch p.format {
+	case formatJSON:
+		if articles == nil {
+			articles = []semanticpen.Article{}
+		}
+		return p.json(articles)
+	case formatTable:
+		return p.table([]string{"ID", "STATUS", "PROGRESS", "CREATED", "TITLE"}, func(row func(...string)) {
+			for _, article := range articles {
+				created := ""
+				if !article.CreatedAt.IsZero() {
+					created = article.CreatedAt.Format("2006-01-02 15:04")
+				}
+				row(article.ID, string(article.Status), strconv.Itoa(article.Progress)+"%", created, article.Title)
+			}
+		})
+	}
+	return p.unsupported("list")
+}
+
+// deleted reports a deleted article
+func (p *printer) deleted(id string) error {
+	if p.format == formatJSON {
+		return p.json(map[string]interface{}{"articleId": id, "deleted": true})
+	}
+	_, err := fmt.Fprintf(p.w, "deleted %s\n", id)
+	return err
+}
+
+// batch prints batch results: a table once the batch is done, or one JSON
+// object per line as each item completes. each is called with every result
+// as it arrives.
+func (p *printer) batch(results <-chan semanticpen.BatchResult, each func(semanticpen.BatchResult) error) error {
+	var tw *tabwriter.Writer
+	switch p.format {
+	case formatTable:
+		tw = tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "#\tKEYWORD\tSTATUS\tARTICLE IDS\tERROR")
+	case formatJSON:
+	default:
+		return p.unsupported("batch")
+	}
+
+	for result := range results {
+		if err := each(result); err != nil {
+			return err
+		}
+		if tw != nil {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", result.Index+1, result.Request.TargetKeyword,
+				result.Status, strings.Join(result.ArticleIDs, ","), errorString(result.Err))
+			continue
+		}
+
+		var titles []string
+		for _, article := range result.Articles {
+			if article != nil {
+				titles = append(titles, article.Title)
+			}
+		}
+		line := map[string]interface{}{
+			"index":      result.Index,
+			"keyword":    result.Request.TargetKeyword,
+			"status":     result.Status,
+			"articleIds": result.ArticleIDs,
+			"titles":     titles,
+		}
+		if result.Err != nil {
+			line["error"] = result.Err.Error()
+		}
+		data, err := json.Marshal(line)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(p.w, "%s\n", data); err != nil {
+			return err
+		}
+	}
+
+	if tw != nil {
+		return tw.Flush()
+	}
+	return nil
+}
+
+func (p *printer) json(v interface{}) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// table writes aligned columns; fill calls row once per line
+func (p *printer) table(header []string, fill func(row func(...string))) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	fill(func(cells ...string) {
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	})
+	return tw.Flush()
+}
+
+func (p *printer) unsupported(what string) error {
+	return fmt.Errorf("%w: output format %q is not supported for %s", errUsage, p.format, what)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
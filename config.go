@@ -0,0 +1,387 @@
+package semanticpen
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by NewClientFromConfig
+const (
+	EnvAPIKey  = "SEMANTICPEN_API_KEY"
+	EnvBaseURL = "SEMANTICPEN_BASE_URL"
+	EnvProfile = "SEMANTICPEN_PROFILE"
+	EnvConfig  = "SEMANTICPEN_CONFIG" // Overrides the config file path
+)
+
+// DefaultProfile is used when no profile is selected
+const DefaultProfile = "default"
+
+// Profile holds the settings for one account or environment
+type Profile struct {
+	Name    string
+	APIKey  string
+	BaseURL string
+	Timeout time.Duration
+	Debug   bool
+}
+
+// ConfigFile is a parsed config file
+type ConfigFile struct {
+	DefaultProfile string              // Profile used when none is selected; DefaultProfile when empty
+	Profiles       map[string]*Profile // Keyed by name
+}
+
+// LoadOptions configures NewClientFromConfig. Settings are resolved from, in
+// order of precedence: the fields below, the environment, then the profile.
+type LoadOptions struct {
+	Path    string  // Config file; defaults to $SEMANTICPEN_CONFIG, then DefaultConfigPath. A missing default file is not an error
+	Profile string  // Profile name; defaults to $SEMANTICPEN_PROFILE, then the file's default profile
+	APIKey  string  // Overrides the environment and profile
+	BaseURL string  // Overrides the environment and profile
+	Config  *Config // Base client configuration; a resolved BaseURL replaces its BaseURL, and the profile's Timeout and Debug fill in when unset
+}
+
+// DefaultConfigPath returns ~/.config/semanticpen/config on every platform,
+// or $XDG_CONFIG_HOME/semanticpen/config when XDG_CONFIG_HOME is set
+func DefaultConfigPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if !filepath.IsAbs(dir) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "semanticpen", "config"), nil
+}
+
+// NewClientFromConfig builds a client from the config file, environment and
+// options
+func NewClientFromConfig(options *LoadOptions) (*Client, error) {
+	profile, err := LoadProfile(options)
+	if err != nil {
+		return nil, err
+	}
+	if profile.APIKey == "" {
+		return nil, &ValidationError{
+			Field:   "apiKey",
+			Message: fmt.Sprintf("no API key: set %s or api_key in profile %q", EnvAPIKey, profile.Name),
+		}
+	}
+
+	config := Config{}
+	if options != nil && options.Config != nil {
+		config = *options.Config
+	}
+	if profile.BaseURL != "" {
+		config.BaseURL = profile.BaseURL
+	}
+	if config.Timeout == 0 {
+		config.Timeout = profile.Timeout
+	}
+	config.Debug = config.Debug || profile.Debug
+
+	return NewClient(profile.APIKey, &config), nil
+}
+
+// LoadProfile resolves the selected profile with environment variables and
+// option overrides applied. Selecting a profile the file does not define is
+// an error, except for DefaultProfile.
+func LoadProfile(options *LoadOptions) (*Profile, error) {
+	if options == nil {
+		options = &LoadOptions{}
+	}
+
+	file, err := loadConfigFile(options.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	name := firstSet(options.Profile, os.Getenv(EnvProfile), file.DefaultProfile, DefaultProfile)
+	profile := &Profile{Name: name}
+	if p, ok := file.Profiles[name]; ok {
+		*profile = *p
+		profile.Name = name
+	} else if name != DefaultProfile {
+		return nil, fmt.Errorf("profile %q not found; available profiles: %s", name, strings.Join(file.profileNames(), ", "))
+	}
+
+	profile.APIKey = firstSet(options.APIKey, os.Getenv(EnvAPIKey), profile.APIKey)
+	profile.BaseURL = firstSet(options.BaseURL, os.Getenv(EnvBaseURL), profile.BaseURL)
+	return profile, nil
+}
+
+// loadConfigFile reads the config file, returning an empty one when the
+// default file does not exist
+func loadConfigFile(path string) (*ConfigFile, error) {
+	explicit := true
+	if path == "" {
+		path = os.Getenv(EnvConfig)
+	}
+	if path == "" {
+		explicit = false
+		var err error
+		if path, err = DefaultConfigPath(); err != nil {
+			return &ConfigFile{}, nil
+		}
+	}
+
+	file, err := LoadConfigFile(path)
+	if err != nil && !explicit && errors.Is(err, os.ErrNotExist) {
+		return &ConfigFile{}, nil
+	}
+	return file, err
+}
+
+// LoadConfigFile reads and parses a config file
+func LoadConfigFile(path string) (*ConfigFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	file, err := ParseConfigFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// ParseConfigFile parses a config file in either format:
+//
+// JSON, with profiles under "profiles", or a single flat profile used as the
+// default:
+//
+//	{"defaultProfile": "production",
+//	 "profiles": {"production": {"apiKey": "...", "baseUrl": "..."}}}
+//
+// TOML-like, with keys before any section applying to the default profile:
+//
+//	default_profile = "production"
+//
+//	[production]
+//	api_key = "..."
+//	timeout = "60s"
+//
+//	[profile staging]
+//	api_key = "..."
+//	base_url = "https://staging.semanticpen.com"
+//
+// Key names ignore case, underscores and hyphens.
+func ParseConfigFile(data []byte) (*ConfigFile, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return parseJSONConfig(trimmed)
+	}
+	return parseTOMLConfig(data)
+}
+
+func parseJSONConfig(data []byte) (*ConfigFile, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	file := &ConfigFile{Profiles: map[string]*Profile{}}
+	flat := &Profile{Name: DefaultProfile}
+	hasFlat := false
+	for key, value := range raw {
+		switch configKey(key) {
+		case "defaultprofile":
+			file.DefaultProfile = fmt.Sprint(value)
+		case "profiles":
+			profiles, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("profiles must be an object")
+			}
+			for name, fields := range profiles {
+				object, ok := fields.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("profile %q must be an object", name)
+				}
+				profile := &Profile{Name: name}
+				for k, v := range object {
+					if err := profile.set(k, jsonString(v)); err != nil {
+						return nil, fmt.Errorf("profile %q: %w", name, err)
+					}
+				}
+				file.Profiles[name] = profile
+			}
+		default:
+			if err := flat.set(key, jsonString(value)); err != nil {
+				return nil, err
+			}
+			hasFlat = true
+		}
+	}
+
+	if hasFlat {
+		if _, ok := file.Profiles[DefaultProfile]; ok {
+			return nil, fmt.Errorf("top-level settings conflict with the %q profile", DefaultProfile)
+		}
+		file.Profiles[DefaultProfile] = flat
+	}
+	return file, nil
+}
+
+func parseTOMLConfig(data []byte) (*ConfigFile, error) {
+	file := &ConfigFile{Profiles: map[string]*Profile{}}
+	var current *Profile
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(stripComment(scanner.Text()))
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section header", line)
+			}
+			name := strings.TrimSpace(text[1 : len(text)-1])
+			name = strings.TrimPrefix(name, "profiles.")
+			name = strings.TrimSpace(strings.TrimPrefix(name, "profile "))
+			name = unquote(name)
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty profile name", line)
+			}
+			if file.Profiles[name] == nil {
+				file.Profiles[name] = &Profile{Name: name}
+			}
+			current = file.Profiles[name]
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", line)
+		}
+		key = strings.TrimSpace(key)
+		value = unquote(strings.TrimSpace(value))
+
+		if current == nil && configKey(key) == "defaultprofile" {
+			file.DefaultProfile = value
+			continue
+		}
+		target := current
+		if target == nil {
+			if file.Profiles[DefaultProfile] == nil {
+				file.Profiles[DefaultProfile] = &Profile{Name: DefaultProfile}
+			}
+			target = file.Profiles[DefaultProfile]
+		}
+		if err := target.set(key, value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// set assigns a profile field from its string form
+func (p *Profile) set(key, value string) error {
+	switch configKey(key) {
+	case "apikey":
+		p.APIKey = value
+	case "baseurl":
+		p.BaseURL = value
+	case "timeout":
+		d, err := parseConfigDuration(value)
+		if err != nil {
+			return fmt.Errorf("timeout: %w", err)
+		}
+		p.Timeout = d
+	case "debug":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("debug: %w", err)
+		}
+		p.Debug = b
+	default:
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	return nil
+}
+
+func (f *ConfigFile) profileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return []string{"none"}
+	}
+	return names
+}
+
+// parseConfigDuration accepts a Go duration or a number of seconds
+func parseConfigDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	return time.ParseDuration(value)
+}
+
+// configKey normalizes a setting name so api_key, apiKey and api-key match
+func configKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(key))
+}
+
+func jsonString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// stripComment removes a # comment that is not inside quotes
+func stripComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		if value[0] == '"' {
+			if s, err := strconv.Unquote(value); err == nil {
+				return s
+			}
+		}
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+func firstSet(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,236 @@
+package semanticpen
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfigFile(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want *ConfigFile
+	}{
+		{
+			name: "json profiles",
+			data: `{"defaultProfile": "production", "profiles": {
+				"production": {"apiKey": "live", "timeout": 45},
+				"staging": {"api_key": "test", "base-url": "https://staging.example", "debug": true}}}`,
+			want: &ConfigFile{DefaultProfile: "production", Profiles: map[string]*Profile{
+				"production": {Name: "production", APIKey: "live", Timeout: 45 * time.Second},
+				"staging":    {Name: "staging", APIKey: "test", BaseURL: "https://staging.example", Debug: true},
+			}},
+		},
+		{
+			name: "flat json",
+			data: "\xef\xbb\xbf" + `{"apiKey": "key", "timeout": "1m"}`,
+			want: &ConfigFile{Profiles: map[string]*Profile{
+				DefaultProfile: {Name: DefaultProfile, APIKey: "key", Timeout: time.Minute},
+			}},
+		},
+		{
+			name: "toml",
+			data: `# SemanticPen
+default_profile = "production"
+api_key = "fallback"   # applies to the default profile
+
+[production]
+api_key = "live#1"
+timeout = "60s"
+
+[profile staging]
+API-Key = 'test'
+base_url = "https://staging.example"
+debug = true
+
+[profiles.qa]
+api_key = qa
+`,
+			want: &ConfigFile{DefaultProfile: "production", Profiles: map[string]*Profile{
+				DefaultProfile: {Name: DefaultProfile, APIKey: "fallback"},
+				"production":   {Name: "production", APIKey: "live#1", Timeout: time.Minute},
+				"staging":      {Name: "staging", APIKey: "test", BaseURL: "https://staging.example", Debug: true},
+				"qa":           {Name: "qa", APIKey: "qa"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseConfigFile([]byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseConfigFile() = %+v, want %+v", got.Profiles, tt.want.Profiles)
+			}
+		})
+	}
+}
+
+func TestParseConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "unknown json setting", data: `{"profiles": {"a": {"region": "eu"}}}`, want: `profile "a": unknown setting "region"`},
+		{name: "flat conflicts with default", data: `{"apiKey": "x", "profiles": {"default": {}}}`, want: "conflict"},
+		{name: "profiles not an object", data: `{"profiles": []}`, want: "profiles must be an object"},
+		{name: "bad timeout", data: "[a]\ntimeout = soon", want: "line 2: timeout:"},
+		{name: "bad debug", data: "debug = maybe", want: "line 1: debug:"},
+		{name: "unterminated section", data: "[production", want: "line 1: unterminated section header"},
+		{name: "missing equals", data: "api_key", want: "line 1: expected key = value"},
+		{name: "empty section", data: "[ ]", want: "line 1: empty profile name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseConfigFile([]byte(tt.data)); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseConfigFile() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// writeConfig writes a config file to a temporary directory and clears the
+// environment variables that would otherwise leak into the test
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	for _, key := range []string{EnvAPIKey, EnvBaseURL, EnvProfile, EnvConfig} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const testConfig = `default_profile = "production"
+
+[production]
+api_key = "file-live"
+base_url = "https://live.example"
+
+[staging]
+api_key = "file-test"
+timeout = "5s"
+`
+
+func TestLoadProfile(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		options     LoadOptions
+		wantName    string
+		wantKey     string
+		wantBaseURL string
+	}{
+		{name: "file default profile", wantName: "production", wantKey: "file-live", wantBaseURL: "https://live.example"},
+		{name: "env profile", env: map[string]string{EnvProfile: "staging"}, wantName: "staging", wantKey: "file-test"},
+		{name: "option profile beats env", env: map[string]string{EnvProfile: "staging"}, options: LoadOptions{Profile: "production"}, wantName: "production", wantKey: "file-live", wantBaseURL: "https://live.example"},
+		{name: "env beats profile", env: map[string]string{EnvAPIKey: "env-key", EnvBaseURL: "https://env.example"}, wantName: "production", wantKey: "env-key", wantBaseURL: "https://env.example"},
+		{name: "option beats env", env: map[string]string{EnvAPIKey: "env-key"}, options: LoadOptions{APIKey: "flag-key", BaseURL: "https://flag.example"}, wantName: "production", wantKey: "flag-key", wantBaseURL: "https://flag.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, testConfig)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			tt.options.Path = path
+
+			profile, err := LoadProfile(&tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if profile.Name != tt.wantName || profile.APIKey != tt.wantKey || profile.BaseURL != tt.wantBaseURL {
+				t.Errorf("LoadProfile() = %+v, want %s with key %s and base URL %q", profile, tt.wantName, tt.wantKey, tt.wantBaseURL)
+			}
+		})
+	}
+}
+
+func TestLoadProfileErrors(t *testing.T) {
+	path := writeConfig(t, testConfig)
+
+	_, err := LoadProfile(&LoadOptions{Path: path, Profile: "qa"})
+	if err == nil || err.Error() != `profile "qa" not found; available profiles: production, staging` {
+		t.Errorf("unknown profile: error = %v", err)
+	}
+
+	if _, err := LoadProfile(&LoadOptions{Path: filepath.Join(t.TempDir(), "missing")}); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing explicit file: error = %v, want os.ErrNotExist", err)
+	}
+
+	t.Setenv(EnvConfig, filepath.Join(t.TempDir(), "missing"))
+	if _, err := LoadProfile(nil); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing $%s file: error = %v, want os.ErrNotExist", EnvConfig, err)
+	}
+}
+
+func TestLoadProfileWithoutFile(t *testing.T) {
+	writeConfig(t, "")
+	t.Setenv(EnvAPIKey, "env-key")
+
+	profile, err := LoadProfile(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Name != DefaultProfile || profile.APIKey != "env-key" {
+		t.Errorf("LoadProfile() = %+v, want the default profile with the env key", profile)
+	}
+}
+
+func TestDefaultConfigPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	path, err := DefaultConfigPath()
+	if err != nil || path != filepath.Join(home, ".config", "semanticpen", "config") {
+		t.Errorf("DefaultConfigPath() = %s, %v", path, err)
+	}
+
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	if path, _ := DefaultConfigPath(); path != filepath.Join(xdg, "semanticpen", "config") {
+		t.Errorf("DefaultConfigPath() with XDG_CONFIG_HOME = %s", path)
+	}
+}
+
+func TestNewClientFromConfig(t *testing.T) {
+	path := writeConfig(t, testConfig)
+
+	client, err := NewClientFromConfig(&LoadOptions{Path: path, Profile: "staging", Config: &Config{BaseURL: "https://base.example"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.apiKey != "file-test" || client.baseURL != "https://base.example" || client.httpClient.Timeout != 5*time.Second {
+		t.Errorf("client = key %s, base URL %s, timeout %v", client.apiKey, client.baseURL, client.httpClient.Timeout)
+	}
+
+	client, err = NewClientFromConfig(&LoadOptions{Path: path, Config: &Config{Timeout: time.Second}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.baseURL != "https://live.example" || client.httpClient.Timeout != time.Second {
+		t.Errorf("client = base URL %s, timeout %v; want the profile URL and the config timeout", client.baseURL, client.httpClient.Timeout)
+	}
+
+	writeConfig(t, "")
+	var validationErr *ValidationError
+	if _, err := NewClientFromConfig(nil); !errors.As(err, &validationErr) || validationErr.Field != "apiKey" {
+		t.Errorf("NewClientFromConfig() without a key: error = %v, want an apiKey ValidationError", err)
+	}
+}
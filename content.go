@@ -0,0 +1,416 @@
+package semanticpen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrNoArticleContent is returned by Article.Content when the API sent no article_json
+var ErrNoArticleContent = errors.New("article has no JSON content")
+
+// SkipChildren can be returned from a WalkSections callback to skip the
+// subsections of the current section
+var SkipChildren = errors.New("skip children")
+
+// ArticleContent is the typed form of Article.ArticleJSON. The decoder is
+// lenient: it accepts the common spellings of each field, ignores values of
+// unexpected types, and keeps unrecognized top-level fields in Extra.
+type ArticleContent struct {
+	Title        string                 `json:"title,omitempty"`
+	Introduction []Block                `json:"introduction,omitempty"`
+	Sections     []Section              `json:"sections,omitempty"`
+	Conclusion   []Block                `json:"conclusion,omitempty"`
+	FAQs         []FAQ                  `json:"faqs,omitempty"`
+	KeyTakeaways []string               `json:"keyTakeaways,omitempty"`
+	Images       []Image                `json:"images,omitempty"`
+	Extra        map[string]interface{} `json:"-"`
+}
+
+// Heading is a section heading with its HTML level (2 for <h2>, ...)
+type Heading struct {
+	Text  string `json:"text"`
+	Level int    `json:"level"`
+}
+
+// Section is a headed part of the article, possibly with nested subsections
+type Section struct {
+	Heading  Heading                `json:"heading"`
+	Blocks   []Block                `json:"blocks,omitempty"`
+	Sections []Section              `json:"sections,omitempty"`
+	Extra    map[string]interface{} `json:"-"`
+}
+
+// BlockType identifies the kind of content block
+type BlockType string
+
+const (
+	BlockParagraph BlockType = "paragraph"
+	BlockList      BlockType = "list"
+	BlockImage     BlockType = "image"
+	BlockQuote     BlockType = "quote"
+	BlockUnknown   BlockType = "unknown"
+)
+
+// Block is a single piece of section content
+type Block struct {
+	Type    BlockType              `json:"type"`
+	Text    string                 `json:"text,omitempty"`    // Paragraph or quote text
+	Items   []string               `json:"items,omitempty"`   // List items
+	Ordered bool                   `json:"ordered,omitempty"` // Whether a list is numbered
+	Image   *Image                 `json:"image,omitempty"`
+	Raw     map[string]interface{} `json:"-"` // Original object, kept for unknown block types
+}
+
+// Image is an image reference in the article
+type Image struct {
+	URL     string `json:"url"`
+	Alt     string `json:"alt,omitempty"`
+	Caption string `json:"caption,omitempty"`
+}
+
+// FAQ is a question and answer pair
+type FAQ struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// Content decodes the article's ArticleJSON into an ArticleContent
+func (a *Article) Content() (*ArticleContent, error) {
+	if len(a.ArticleJSON) == 0 {
+		return nil, ErrNoArticleContent
+	}
+	return ParseArticleContent(a.ArticleJSON), nil
+}
+
+// ParseArticleContent leniently decodes an article_json object
+func ParseArticleContent(raw map[string]interface{}) *ArticleContent {
+	d := decoder{fields: raw, used: map[string]bool{}}
+	content := &ArticleContent{
+		Title:        d.text("title", "h1", "headline"),
+		Introduction: parseBlocks(d.value("introduction", "intro")),
+		Conclusion:   parseBlocks(d.value("conclusion", "summary")),
+		KeyTakeaways: parseStrings(d.value("keyTakeaways", "key_takeaways", "takeaways")),
+	}
+
+	for _, item := range asSlice(d.value("sections", "content", "body")) {
+		if fields, ok := item.(map[string]interface{}); ok {
+			content.Sections = append(content.Sections, parseSection(fields, 2))
+		}
+	}
+
+	for _, item := range asSlice(d.value("faqs", "faq", "FAQ", "FAQs")) {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fd := decoder{fields: fields, used: map[string]bool{}}
+		faq := FAQ{Question: fd.text("question", "q"), Answer: fd.text("answer", "a")}
+		if faq.Question != "" {
+			content.FAQs = append(content.FAQs, faq)
+		}
+	}
+
+	if featured := parseImage(d.value("featuredImage", "featured_image", "image")); featured != nil {
+		content.Images = append(content.Images, *featured)
+	}
+	for _, item := range asSlice(d.value("images")) {
+		if image := parseImage(item); image != nil {
+			content.Images = append(content.Images, *image)
+		}
+	}
+
+	content.Extra = d.rest()
+	return content
+}
+
+// UnmarshalJSON decodes raw article_json with the same leniency as ParseArticleContent
+func (c *ArticleContent) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid article content: %w", err)
+	}
+	*c = *ParseArticleContent(raw)
+	return nil
+}
+
+// WalkSections calls fn for every section, depth first, with the nesting depth
+// starting at 0. Returning SkipChildren skips the section's subsections; any
+// other error stops the walk and is returned.
+func (c *ArticleContent) WalkSections(fn func(section *Section, depth int) error) error {
+	var walk func(sections []Section, depth int) error
+	walk = func(sections []Section, depth int) error {
+		for i := range sections {
+			err := fn(&sections[i], depth)
+			if err == SkipChildren {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := walk(sections[i].Sections, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(c.Sections, 0)
+}
+
+// Headings returns every section heading in document order
+func (c *ArticleContent) Headings() []Heading {
+	var headings []Heading
+	c.WalkSections(func(section *Section, depth int) error {
+		if section.Heading.Text != "" {
+			headings = append(headings, section.Heading)
+		}
+		return nil
+	})
+	return headings
+}
+
+// Paragraphs returns the text of every paragraph in document order
+func (c *ArticleContent) Paragraphs() []string {
+	var paragraphs []string
+	collect := func(blocks []Block) {
+		for _, block := range blocks {
+			if block.Type == BlockParagraph && block.Text != "" {
+				paragraphs = append(paragraphs, block.Text)
+			}
+		}
+	}
+
+	collect(c.Introduction)
+	c.WalkSections(func(section *Section, depth int) error {
+		collect(section.Blocks)
+		return nil
+	})
+	collect(c.Conclusion)
+	return paragraphs
+}
+
+// AllImages returns the top-level images followed by every image block
+func (c *ArticleContent) AllImages() []Image {
+	images := append([]Image(nil), c.Images...)
+	collect := func(blocks []Block) {
+		for _, block := range blocks {
+			if block.Image != nil {
+				images = append(images, *block.Image)
+			}
+		}
+	}
+
+	collect(c.Introduction)
+	c.WalkSections(func(section *Section, depth int) error {
+		collect(section.Blocks)
+		return nil
+	})
+	collect(c.Conclusion)
+	return images
+}
+
+// parseSection decodes a section object; level is used when it has none
+func parseSection(fields map[string]interface{}, level int) Section {
+	d := decoder{fields: fields, used: map[string]bool{}}
+	section := Section{Heading: Heading{Level: level}}
+
+	for _, key := range []string{"h2", "h3", "h4", "h5", "h6"} {
+		if text, ok := fields[key].(string); ok {
+			d.used[key] = true
+			section.Heading = Heading{Text: text, Level: int(key[1] - '0')}
+		}
+	}
+
+	switch heading := d.value("heading", "title", "headline").(type) {
+	case string:
+		section.Heading.Text = heading
+	case map[string]interface{}:
+		hd := decoder{fields: heading, used: map[string]bool{}}
+		section.Heading.Text = hd.text("text", "title")
+		if n := hd.number("level"); n > 0 {
+			section.Heading.Level = n
+		}
+	}
+	if n := d.number("level"); n > 0 {
+		section.Heading.Level = n
+	}
+
+	section.Blocks = parseBlocks(d.value("blocks", "content", "paragraphs", "body", "text"))
+	for _, item := range asSlice(d.value("subsections", "sections", "children")) {
+		if child, ok := item.(map[string]interface{}); ok {
+			section.Sections = append(section.Sections, parseSection(child, section.Heading.Level+1))
+		}
+	}
+
+	section.Extra = d.rest()
+	return section
+}
+
+// parseBlocks decodes a string, or a list of strings and block objects
+func parseBlocks(value interface{}) []Block {
+	if text, ok := value.(string); ok {
+		if text = strings.TrimSpace(text); text != "" {
+			return []Block{{Type: BlockParagraph, Text: text}}
+		}
+		return nil
+	}
+
+	var blocks []Block
+	for _, item := range asSlice(value) {
+		switch item := item.(type) {
+		case string:
+			if text := strings.TrimSpace(item); text != "" {
+				blocks = append(blocks, Block{Type: BlockParagraph, Text: text})
+			}
+		case map[string]interface{}:
+			blocks = append(blocks, parseBlock(item))
+		}
+	}
+	return blocks
+}
+
+// parseBlock decodes a block object, inferring its type when it is not given
+func parseBlock(fields map[string]interface{}) Block {
+	d := decoder{fields: fields, used: map[string]bool{}}
+	block := Block{Raw: fields}
+
+	kind := strings.ToLower(d.text("type", "kind"))
+	items := d.value("items", "list")
+	switch {
+	case kind == "paragraph" || kind == "p" || kind == "text":
+		block.Type = BlockParagraph
+	case kind == "list" || kind == "ul" || kind == "ol" || (kind == "" && items != nil):
+		block.Type = BlockList
+	case kind == "image" || kind == "img" || (kind == "" && (fields["src"] != nil || fields["url"] != nil || fields["image"] != nil)):
+		block.Type = BlockImage
+	case kind == "quote" || kind == "blockquote":
+		block.Type = BlockQuote
+	case kind == "" && (fields["text"] != nil || fields["content"] != nil):
+		block.Type = BlockParagraph
+	default:
+		block.Type = BlockUnknown
+	}
+
+	switch block.Type {
+	case BlockParagraph, BlockQuote:
+		block.Text = d.text("text", "content", "value")
+	case BlockList:
+		block.Items = parseStrings(items)
+		block.Ordered = kind == "ol" || d.flag("ordered", "numbered")
+	case BlockImage:
+		block.Image = parseImage(d.value("image"))
+		if block.Image == nil {
+			block.Image = parseImage(fields)
+		}
+	}
+	return block
+}
+
+// parseImage decodes an image given as a URL string or an object
+func parseImage(value interface{}) *Image {
+	switch value := value.(type) {
+	case string:
+		if value != "" {
+			return &Image{URL: value}
+		}
+	case map[string]interface{}:
+		d := decoder{fields: value, used: map[string]bool{}}
+		image := &Image{
+			URL:     d.text("url", "src", "href"),
+			Alt:     d.text("alt", "altText", "alt_text"),
+			Caption: d.text("caption", "title"),
+		}
+		if image.URL != "" {
+			return image
+		}
+	}
+	return nil
+}
+
+// parseStrings decodes a list of strings, taking "text" from object items
+func parseStrings(value interface{}) []string {
+	var out []string
+	for _, item := range asSlice(value) {
+		switch item := item.(type) {
+		case string:
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		case map[string]interface{}:
+			d := decoder{fields: item, used: map[string]bool{}}
+			if text := d.text("text", "content", "value"); text != "" {
+				out = append(out, text)
+			}
+		}
+	}
+	return out
+}
+
+// asSlice returns value as a slice, or nil if it is not one
+func asSlice(value interface{}) []interface{} {
+	items, _ := value.([]interface{})
+	return items
+}
+
+// decoder reads loosely-typed JSON object fields, remembering which keys were consumed
+type decoder struct {
+	fields map[string]interface{}
+	used   map[string]bool
+}
+
+// value returns the first present key's value
+func (d decoder) value(keys ...string) interface{} {
+	for _, key := range keys {
+		if value, ok := d.fields[key]; ok && value != nil {
+			d.used[key] = true
+			return value
+		}
+	}
+	return nil
+}
+
+// text returns the first present key's value as a trimmed string
+func (d decoder) text(keys ...string) string {
+	switch value := d.value(keys...).(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return ""
+}
+
+// number returns the first present key's value as an int
+func (d decoder) number(keys ...string) int {
+	switch value := d.value(keys...).(type) {
+	case float64:
+		return int(value)
+	case string:
+		n, _ := strconv.Atoi(strings.TrimSpace(value))
+		return n
+	}
+	return 0
+}
+
+// flag returns the first present key's value as a bool
+func (d decoder) flag(keys ...string) bool {
+	value, _ := d.value(keys...).(bool)
+	return value
+}
+
+// rest returns the fields that were not consumed, or nil if there are none
+func (d decoder) rest() map[string]interface{} {
+	var extra map[string]interface{}
+	for key, value := range d.fields {
+		if d.used[key] {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]interface{})
+		}
+		extra[key] = value
+	}
+	return extra
+}
@@ -0,0 +1,188 @@
+package semanticpen
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parseContent(t *testing.T, data string) *ArticleContent {
+	t.Helper()
+	var content ArticleContent
+	if err := json.Unmarshal([]byte(data), &content); err != nil {
+		t.Fatal(err)
+	}
+	return &content
+}
+
+func TestParseArticleContentSpellings(t *testing.T) {
+	want := &ArticleContent{
+		Title:        "Cold Brew",
+		Introduction: []Block{{Type: BlockParagraph, Text: "Why cold brew."}},
+		Sections: []Section{{
+			Heading: Heading{Text: "Equipment", Level: 2},
+			Blocks:  []Block{{Type: BlockParagraph, Text: "A jar."}},
+		}},
+		Conclusion:   []Block{{Type: BlockParagraph, Text: "Enjoy."}},
+		FAQs:         []FAQ{{Question: "How long?", Answer: "12 hours."}},
+		KeyTakeaways: []string{"Use coarse grounds"},
+		Images:       []Image{{URL: "https://example.com/brew.jpg", Alt: "Brew"}},
+	}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "canonical",
+			data: `{"title": "Cold Brew", "introduction": "Why cold brew.",
+				"sections": [{"heading": "Equipment", "blocks": ["A jar."]}],
+				"conclusion": ["Enjoy."], "faqs": [{"question": "How long?", "answer": "12 hours."}],
+				"keyTakeaways": ["Use coarse grounds"], "featuredImage": {"url": "https://example.com/brew.jpg", "alt": "Brew"}}`,
+		},
+		{
+			name: "snake case and short forms",
+			data: `{"h1": "Cold Brew", "intro": "Why cold brew.",
+				"content": [{"h2": "Equipment", "paragraphs": [{"text": "A jar."}]}],
+				"summary": "Enjoy.", "faq": [{"q": "How long?", "a": "12 hours."}],
+				"key_takeaways": [{"text": "Use coarse grounds"}], "featured_image": {"src": "https://example.com/brew.jpg", "alt_text": "Brew"}}`,
+		},
+		{
+			name: "alternate names",
+			data: `{"headline": " Cold Brew ", "intro": ["Why cold brew."],
+				"body": [{"title": {"text": "Equipment", "level": 2}, "content": [{"type": "p", "content": "A jar."}]}],
+				"conclusion": "Enjoy.", "FAQs": [{"question": "How long?", "answer": "12 hours."}, {"answer": "no question"}],
+				"takeaways": ["Use coarse grounds", " "], "images": [{"href": "https://example.com/brew.jpg", "altText": "Brew"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseContent(t, tt.data)
+			for i := range got.Sections {
+				got.Sections[i].Blocks = stripRaw(got.Sections[i].Blocks)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseArticleContent() =\n%+v\nwant\n%+v", got, want)
+			}
+		})
+	}
+}
+
+// stripRaw clears Block.Raw so blocks decoded from objects compare equal to
+// blocks decoded from strings
+func stripRaw(blocks []Block) []Block {
+	for i := range blocks {
+		blocks[i].Raw = nil
+	}
+	return blocks
+}
+
+func TestParseArticleContentBlocks(t *testing.T) {
+	content := parseContent(t, `{"sections": [{"heading": "Steps", "blocks": [
+		{"type": "ol", "items": ["Grind", "Steep"]},
+		{"items": [{"text": "Filter"}], "numbered": true},
+		{"src": "https://example.com/jar.jpg", "caption": "The jar"},
+		{"type": "blockquote", "text": "Patience."},
+		{"type": "table", "rows": 2}
+	]}]}`)
+
+	blocks := content.Sections[0].Blocks
+	want := []Block{
+		{Type: BlockList, Items: []string{"Grind", "Steep"}, Ordered: true},
+		{Type: BlockList, Items: []string{"Filter"}, Ordered: true},
+		{Type: BlockImage, Image: &Image{URL: "https://example.com/jar.jpg", Caption: "The jar"}},
+		{Type: BlockQuote, Text: "Patience."},
+		{Type: BlockUnknown},
+	}
+	if blocks[4].Raw["rows"] != float64(2) {
+		t.Errorf("unknown block Raw = %v, want the original object", blocks[4].Raw)
+	}
+	if got := stripRaw(blocks); !reflect.DeepEqual(got, want) {
+		t.Errorf("blocks =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseArticleContentExtra(t *testing.T) {
+	content := parseContent(t, `{"title": "Cold Brew", "wordCount": 1200, "meta": {"slug": "cold-brew"},
+		"sections": [{"h3": "Ratio", "readingTime": "2m", "blocks": ["1:8"]}]}`)
+
+	wantExtra := map[string]interface{}{"wordCount": float64(1200), "meta": map[string]interface{}{"slug": "cold-brew"}}
+	if !reflect.DeepEqual(content.Extra, wantExtra) {
+		t.Errorf("Extra = %v, want %v", content.Extra, wantExtra)
+	}
+	section := content.Sections[0]
+	if section.Heading != (Heading{Text: "Ratio", Level: 3}) {
+		t.Errorf("heading = %+v, want level 3 from the h3 key", section.Heading)
+	}
+	if !reflect.DeepEqual(section.Extra, map[string]interface{}{"readingTime": "2m"}) {
+		t.Errorf("section Extra = %v, want the unknown field", section.Extra)
+	}
+
+	if parseContent(t, `{"title": "Only known fields"}`).Extra != nil {
+		t.Error("Extra should be nil when every field is recognized")
+	}
+}
+
+func TestArticleContent(t *testing.T) {
+	if _, err := (&Article{}).Content(); !errors.Is(err, ErrNoArticleContent) {
+		t.Errorf("Content() error = %v, want ErrNoArticleContent", err)
+	}
+
+	var content ArticleContent
+	if err := json.Unmarshal([]byte(`["not", "an", "object"]`), &content); err == nil {
+		t.Error("UnmarshalJSON() should reject a non-object")
+	}
+}
+
+func TestWalkSections(t *testing.T) {
+	content := parseContent(t, `{"sections": [
+		{"heading": "A", "subsections": [
+			{"heading": "A1", "children": [{"heading": "A1a"}]},
+			{"heading": "A2"}
+		]},
+		{"heading": "B", "sections": [{"heading": "B1"}]},
+		{"heading": "C"}
+	]}`)
+
+	visit := func(skip, stop string) (string, error) {
+		var visited []string
+		err := content.WalkSections(func(section *Section, depth int) error {
+			visited = append(visited, strings.Repeat(">", depth)+section.Heading.Text)
+			switch section.Heading.Text {
+			case skip:
+				return SkipChildren
+			case stop:
+				return errors.New("stop")
+			}
+			return nil
+		})
+		return strings.Join(visited, " "), err
+	}
+
+	tests := []struct {
+		name, skip, stop string
+		want             string
+		wantErr          bool
+	}{
+		{name: "full walk", want: "A >A1 >>A1a >A2 B >B1 C"},
+		{name: "skip children", skip: "A", want: "A B >B1 C"},
+		{name: "skip nested children", skip: "A1", want: "A >A1 >A2 B >B1 C"},
+		{name: "stop on error", stop: "A2", want: "A >A1 >>A1a >A2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := visit(tt.skip, tt.stop)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("visited %q, error %v; want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	if got := content.Headings(); len(got) != 7 || got[2] != (Heading{Text: "A1a", Level: 4}) {
+		t.Errorf("Headings() = %+v, want nested levels", got)
+	}
+}
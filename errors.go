@@ -1,6 +1,9 @@
 package semanticpen
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // APIError represents an API error response
 type APIError struct {
@@ -37,4 +40,42 @@ func (e *RateLimitError) Error() string {
 		return fmt.Sprintf("rate limit exceeded: %s (retry after %d seconds)", e.Message, e.RetryAfter)
 	}
 	return fmt.Sprintf("rate limit exceeded: %s", e.Message)
-}
\ No newline at end of file
+}
+
+// UnknownStatusError is returned while waiting for an article when the API
+// reports a status the SDK does not recognize
+type UnknownStatusError struct {
+	ArticleID string        `json:"articleId"`
+	Status    ArticleStatus `json:"status"`
+}
+
+func (e *UnknownStatusError) Error() string {
+	return fmt.Sprintf("article %s has unrecognized status %q", e.ArticleID, e.Status)
+}
+
+// StatusRegressionError is returned while waiting for an article when its
+// status moves backwards, e.g. from processing to pending
+type StatusRegressionError struct {
+	ArticleID string        `json:"articleId"`
+	Previous  ArticleStatus `json:"previous"`
+	Status    ArticleStatus `json:"status"`
+}
+
+func (e *StatusRegressionError) Error() string {
+	return fmt.Sprintf("article %s status regressed from %q to %q", e.ArticleID, e.Previous, e.Status)
+}
+
+// TimeoutError is returned when an article is still generating after the
+// configured number of polling attempts or the configured wait timeout
+type TimeoutError struct {
+	ArticleID string        `json:"articleId"`
+	Attempts  int           `json:"attempts"`
+	Timeout   time.Duration `json:"timeout,omitempty"`
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Timeout > 0 {
+		return fmt.Sprintf("article generation timeout after %v (%d attempts)", e.Timeout, e.Attempts)
+	}
+	return fmt.Sprintf("article generation timeout after %d attempts", e.Attempts)
+}
@@ -0,0 +1,115 @@
+package semanticpen
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+const (
+	// IdempotencyKeyHeader is the header carrying GenerateArticleRequest.IdempotencyKey
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// DefaultIdempotencyTTL is how long a generation response is remembered per key
+	DefaultIdempotencyTTL = 24 * time.Hour
+)
+
+// idempotencyCache remembers generation responses by idempotency key, so a
+// repeated request returns the original response instead of creating (and
+// paying for) another article. Concurrent requests with the same key share a
+// single call to the API.
+type idempotencyCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*idempotencyEntry
+}
+
+// idempotencyEntry is an in-flight or completed generation
+type idempotencyEntry struct {
+	done     chan struct{}
+	response *GenerateArticleResponse
+	err      error
+	expires  time.Time
+}
+
+// newIdempotencyCache creates a cache whose entries live for ttl
+func newIdempotencyCache(ttl time.Duration) *idempotencyCache {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	return &idempotencyCache{ttl: ttl, entries: make(map[string]*idempotencyEntry)}
+}
+
+// do returns the cached response for key, waits for an in-flight request with
+// the same key, or calls generate. Failed calls are not cached, so the next
+// caller with the same key tries again.
+func (c *idempotencyCache) do(ctx context.Context, key string, generate func() (*GenerateArticleResponse, error)) (*GenerateArticleResponse, error) {
+	for {
+		c.mu.Lock()
+		c.prune(time.Now())
+		entry, ok := c.entries[key]
+		if !ok {
+			entry = &idempotencyEntry{done: make(chan struct{})}
+			c.entries[key] = entry
+			c.mu.Unlock()
+
+			response, err := generate()
+
+			c.mu.Lock()
+			entry.response, entry.err = response, err
+			if err != nil {
+				delete(c.entries, key)
+			} else {
+				entry.expires = time.Now().Add(c.ttl)
+			}
+			close(entry.done)
+			c.mu.Unlock()
+
+			if err != nil {
+				return nil, err
+			}
+			return copyGenerateResponse(response), nil
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-entry.done:
+			if entry.err == nil {
+				return copyGenerateResponse(entry.response), nil
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// prune drops completed entries whose TTL has passed; callers hold c.mu
+func (c *idempotencyCache) prune(now time.Time) {
+	for key, entry := range c.entries {
+		if !entry.expires.IsZero() && now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// copyGenerateResponse returns a copy that callers may modify freely
+func copyGenerateResponse(r *GenerateArticleResponse) *GenerateArticleResponse {
+	response := *r
+	response.ArticleIDs = append([]string(nil), r.ArticleIDs...)
+	return &response
+}
+
+// newIdempotencyKey returns a random version 4 UUID
+func newIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
@@ -0,0 +1,175 @@
+package semanticpen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingGenerate returns a generate function that counts its calls and
+// answers with an article ID numbered by call
+func countingGenerate(calls *int32, delay time.Duration, err error) func() (*GenerateArticleResponse, error) {
+	return func() (*GenerateArticleResponse, error) {
+		n := atomic.AddInt32(calls, 1)
+		time.Sleep(delay)
+		if err != nil {
+			return nil, err
+		}
+		return &GenerateArticleResponse{ArticleIDs: []string{fmt.Sprintf("a%d", n)}}, nil
+	}
+}
+
+func TestIdempotencyCacheDeduplicates(t *testing.T) {
+	cache := newIdempotencyCache(0)
+	var calls int32
+
+	first, err := cache.do(context.Background(), "k1", countingGenerate(&calls, 0, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.ArticleIDs[0] = "modified"
+
+	second, err := cache.do(context.Background(), "k1", countingGenerate(&calls, 0, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || second.ArticleIDs[0] != "a1" {
+		t.Errorf("%d calls, second response %v; want one call and an unmodified cached response", calls, second.ArticleIDs)
+	}
+
+	if other, _ := cache.do(context.Background(), "k2", countingGenerate(&calls, 0, nil)); calls != 2 || other.ArticleIDs[0] != "a2" {
+		t.Errorf("a different key shared the cached response: %v", other.ArticleIDs)
+	}
+}
+
+func TestIdempotencyCacheExpires(t *testing.T) {
+	cache := newIdempotencyCache(10 * time.Millisecond)
+	var calls int32
+
+	cache.do(context.Background(), "k1", countingGenerate(&calls, 0, nil))
+	time.Sleep(20 * time.Millisecond)
+	response, err := cache.do(context.Background(), "k1", countingGenerate(&calls, 0, nil))
+	if err != nil || calls != 2 || response.ArticleIDs[0] != "a2" {
+		t.Errorf("after the TTL: %d calls, response %+v, error %v; want a fresh call", calls, response, err)
+	}
+	if len(cache.entries) != 1 {
+		t.Errorf("%d cache entries, want expired ones pruned", len(cache.entries))
+	}
+}
+
+func TestIdempotencyCacheDoesNotCacheErrors(t *testing.T) {
+	cache := newIdempotencyCache(0)
+	var calls int32
+	failure := errors.New("server error")
+
+	if _, err := cache.do(context.Background(), "k1", countingGenerate(&calls, 0, failure)); !errors.Is(err, failure) {
+		t.Fatalf("do() error = %v, want %v", err, failure)
+	}
+	response, err := cache.do(context.Background(), "k1", countingGenerate(&calls, 0, nil))
+	if err != nil || calls != 2 || response.ArticleIDs[0] != "a2" {
+		t.Errorf("after a failure: %d calls, response %+v, error %v; want a retry", calls, response, err)
+	}
+}
+
+func TestIdempotencyCacheConcurrent(t *testing.T) {
+	cache := newIdempotencyCache(0)
+	var calls int32
+
+	var wg sync.WaitGroup
+	responses := make([]*GenerateArticleResponse, 20)
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			response, err := cache.do(context.Background(), "shared", countingGenerate(&calls, 20*time.Millisecond, nil))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			responses[i] = response
+		}(i)
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("%d calls for concurrent requests with one key, want 1", calls)
+	}
+	for i, response := range responses {
+		if response == nil || response.ArticleIDs[0] != "a1" {
+			t.Errorf("response %d = %+v, want the shared response", i, response)
+		}
+	}
+}
+
+func TestIdempotencyCacheWaiterRetriesAfterFailure(t *testing.T) {
+	cache := newIdempotencyCache(0)
+	var calls int32
+
+	started := make(chan struct{})
+	failing := func() (*GenerateArticleResponse, error) {
+		atomic.AddInt32(&calls, 1)
+		close(started)
+		time.Sleep(20 * time.Millisecond)
+		return nil, errors.New("server error")
+	}
+	go cache.do(context.Background(), "k1", failing)
+	<-started
+
+	response, err := cache.do(context.Background(), "k1", countingGenerate(&calls, 0, nil))
+	if err != nil || calls != 2 || response.ArticleIDs[0] != "a2" {
+		t.Errorf("%d calls, response %+v, error %v; want the waiter to generate after the failure", calls, response, err)
+	}
+}
+
+func TestIdempotencyCacheWaiterHonorsContext(t *testing.T) {
+	cache := newIdempotencyCache(0)
+	var calls int32
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go cache.do(context.Background(), "k1", func() (*GenerateArticleResponse, error) {
+		close(started)
+		<-release
+		return &GenerateArticleResponse{}, nil
+	})
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := cache.do(ctx, "k1", countingGenerate(&calls, 0, nil)); !errors.Is(err, context.DeadlineExceeded) || calls != 0 {
+		t.Errorf("do() error = %v after %d calls, want context.DeadlineExceeded without calling generate", err, calls)
+	}
+}
+
+func TestGenerateArticleIdempotencyKey(t *testing.T) {
+	var requests int32
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		fmt.Fprint(w, `{"articleIds": ["a1"]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("key", &Config{BaseURL: server.URL})
+	for i := 0; i < 2; i++ {
+		response, err := client.GenerateArticle("cold brew", &GenerateArticleRequest{IdempotencyKey: "order-1"})
+		if id, _ := response.GetArticleID(); err != nil || id != "a1" {
+			t.Fatalf("GenerateArticle() = %+v, %v", response, err)
+		}
+	}
+	if requests != 1 || keys[0] != "order-1" {
+		t.Errorf("%d requests with keys %q, want one request carrying the key", requests, keys)
+	}
+
+	if _, err := client.GenerateArticle("cold brew", nil); err != nil || requests != 2 || keys[1] != "" {
+		t.Errorf("unkeyed request: %d requests, keys %q, error %v", requests, keys, err)
+	}
+}
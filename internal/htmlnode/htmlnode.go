@@ -0,0 +1,491 @@
+// Package htmlnode is a small, forgiving HTML parser and renderer used to
+// post-process generated article HTML without external dependencies. It
+// handles the HTML found in articles (void elements, raw text elements,
+// implied end tags for p, li and table parts) rather than the full HTML5
+// parsing algorithm.
+package htmlnode
+
+import (
+	"html"
+	"io"
+	"strings"
+)
+
+// NodeType identifies the kind of a Node
+type NodeType int
+
+const (
+	DocumentNode NodeType = iota
+	ElementNode
+	TextNode
+	CommentNode
+	DoctypeNode
+)
+
+// Attr is an element attribute
+type Attr struct {
+	Key string
+	Val string
+}
+
+// Node is an element, text, comment or doctype in a parsed document
+type Node struct {
+	Type     NodeType
+	Data     string // Lowercase tag name for elements, unescaped text otherwise
+	Attr     []Attr
+	Parent   *Node
+	Children []*Node
+}
+
+// voidElements never have children or end tags
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true,
+	"img": true, "input": true, "link": true, "meta": true, "source": true,
+	"track": true, "wbr": true,
+}
+
+// rawTextElements contain text that is not parsed as markup
+var rawTextElements = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true,
+}
+
+// closesParagraph are start tags that implicitly end an open <p>
+var closesParagraph = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "div": true,
+	"dl": true, "fieldset": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "main": true, "nav": true, "ol": true, "p": true,
+	"pre": true, "section": true, "table": true, "ul": true,
+}
+
+// IsVoid reports whether tag is a void element
+func IsVoid(tag string) bool {
+	return voidElements[tag]
+}
+
+// Parse parses an HTML fragment or document into a tree rooted at a DocumentNode
+func Parse(s string) *Node {
+	p := &parser{src: s, doc: &Node{Type: DocumentNode}}
+	p.stack = []*Node{p.doc}
+	p.parse()
+	return p.doc
+}
+
+// parser builds a Node tree from HTML source
+type parser struct {
+	src   string
+	pos   int
+	doc   *Node
+	stack []*Node
+}
+
+func (p *parser) current() *Node {
+	return p.stack[len(p.stack)-1]
+}
+
+func (p *parser) parse() {
+	for p.pos < len(p.src) {
+		lt := strings.IndexByte(p.src[p.pos:], '<')
+		if lt < 0 {
+			p.addText(p.src[p.pos:])
+			return
+		}
+		if lt > 0 {
+			p.addText(p.src[p.pos : p.pos+lt])
+			p.pos += lt
+		}
+
+		rest := p.src[p.pos:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				p.append(&Node{Type: CommentNode, Data: rest[4:]})
+				p.pos = len(p.src)
+				return
+			}
+			p.append(&Node{Type: CommentNode, Data: rest[4 : 4+end]})
+			p.pos += 4 + end + 3
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			if strings.HasPrefix(strings.ToLower(rest), "<!doctype") {
+				p.append(&Node{Type: DoctypeNode, Data: strings.TrimSpace(rest[9:end])})
+			}
+			p.pos += end + 1
+		case strings.HasPrefix(rest, "</") && len(rest) > 2 && isNameStart(rest[2]):
+			p.parseEndTag()
+		case len(rest) > 1 && isNameStart(rest[1]):
+			p.parseStartTag()
+		default:
+			p.addText("<")
+			p.pos++
+		}
+	}
+}
+
+// parseEndTag consumes "</name ...>" and closes the matching open element
+func (p *parser) parseEndTag() {
+	start := p.pos + 2
+	end := start
+	for end < len(p.src) && isNameChar(p.src[end]) {
+		end++
+	}
+	name := strings.ToLower(p.src[start:end])
+
+	gt := strings.IndexByte(p.src[end:], '>')
+	if gt < 0 {
+		p.pos = len(p.src)
+	} else {
+		p.pos = end + gt + 1
+	}
+
+	for i := len(p.stack) - 1; i > 0; i-- {
+		if p.stack[i].Data == name {
+			p.stack = p.stack[:i]
+			return
+		}
+	}
+}
+
+// parseStartTag consumes "<name attr=value ...>" and opens the element
+func (p *parser) parseStartTag() {
+	i := p.pos + 1
+	start := i
+	for i < len(p.src) && isNameChar(p.src[i]) {
+		i++
+	}
+	node := &Node{Type: ElementNode, Data: strings.ToLower(p.src[start:i])}
+
+	selfClosing := false
+	for i < len(p.src) {
+		for i < len(p.src) && isSpace(p.src[i]) {
+			i++
+		}
+		if i >= len(p.src) {
+			break
+		}
+		if p.src[i] == '>' {
+			i++
+			break
+		}
+		if p.src[i] == '/' {
+			selfClosing = true
+			i++
+			continue
+		}
+
+		keyStart := i
+		for i < len(p.src) && !isSpace(p.src[i]) && p.src[i] != '=' && p.src[i] != '>' && p.src[i] != '/' {
+			i++
+		}
+		key := strings.ToLower(p.src[keyStart:i])
+		for i < len(p.src) && isSpace(p.src[i]) {
+			i++
+		}
+
+		val := ""
+		if i < len(p.src) && p.src[i] == '=' {
+			i++
+			for i < len(p.src) && isSpace(p.src[i]) {
+				i++
+			}
+			if i < len(p.src) && (p.src[i] == '"' || p.src[i] == '\'') {
+				quote := p.src[i]
+				i++
+				valStart := i
+				for i < len(p.src) && p.src[i] != quote {
+					i++
+				}
+				val = p.src[valStart:i]
+				if i < len(p.src) {
+					i++
+				}
+			} else {
+				valStart := i
+				for i < len(p.src) && !isSpace(p.src[i]) && p.src[i] != '>' {
+					i++
+				}
+				val = p.src[valStart:i]
+			}
+		}
+		if key != "" && node.AttrIndex(key) < 0 {
+			node.Attr = append(node.Attr, Attr{Key: key, Val: html.UnescapeString(val)})
+		}
+		selfClosing = false
+	}
+	p.pos = i
+
+	p.closeImplied(node.Data)
+	p.append(node)
+
+	if voidElements[node.Data] || selfClosing {
+		return
+	}
+
+	if rawTextElements[node.Data] {
+		closing := "</" + node.Data
+		end := indexFold(p.src[p.pos:], closing)
+		if end < 0 {
+			end = len(p.src) - p.pos
+		}
+		text := p.src[p.pos : p.pos+end]
+		if node.Data == "textarea" || node.Data == "title" {
+			text = html.UnescapeString(text)
+		}
+		if text != "" {
+			node.AppendChild(&Node{Type: TextNode, Data: text})
+		}
+		p.pos += end
+		if gt := strings.IndexByte(p.src[p.pos:], '>'); gt >= 0 {
+			p.pos += gt + 1
+		} else {
+			p.pos = len(p.src)
+		}
+		return
+	}
+
+	p.stack = append(p.stack, node)
+}
+
+// closeImplied pops elements whose end tag is implied by the start of tag
+func (p *parser) closeImplied(tag string) {
+	switch {
+	case closesParagraph[tag]:
+		p.popUntil("p", "button", "td", "th", "li", "blockquote", "div", "section", "article")
+	case tag == "li":
+		p.popUntil("li", "ul", "ol")
+	case tag == "dt" || tag == "dd":
+		p.popUntil("dt", "dl")
+		p.popUntil("dd", "dl")
+	case tag == "tr":
+		p.popUntil("td", "tr", "table")
+		p.popUntil("th", "tr", "table")
+		p.popUntil("tr", "table", "thead", "tbody", "tfoot")
+	case tag == "td" || tag == "th":
+		p.popUntil("td", "tr", "table")
+		p.popUntil("th", "tr", "table")
+	case tag == "thead" || tag == "tbody" || tag == "tfoot":
+		p.popUntil("tr", "table")
+		p.popUntil("thead", "table")
+		p.popUntil("tbody", "table")
+	case tag == "option":
+		p.popUntil("option", "select")
+	}
+}
+
+// popUntil closes the innermost open target element, unless a boundary
+// element is found first
+func (p *parser) popUntil(target string, boundaries ...string) {
+	for i := len(p.stack) - 1; i > 0; i-- {
+		tag := p.stack[i].Data
+		if tag == target {
+			p.stack = p.stack[:i]
+			return
+		}
+		for _, boundary := range boundaries {
+			if tag == boundary && boundary != target {
+				return
+			}
+		}
+	}
+}
+
+func (p *parser) append(n *Node) {
+	p.current().AppendChild(n)
+}
+
+func (p *parser) addText(s string) {
+	if s == "" {
+		return
+	}
+	text := html.UnescapeString(s)
+	parent := p.current()
+	if last := parent.LastChild(); last != nil && last.Type == TextNode {
+		last.Data += text
+		return
+	}
+	parent.AppendChild(&Node{Type: TextNode, Data: text})
+}
+
+// AppendChild adds c as the last child of n
+func (n *Node) AppendChild(c *Node) {
+	c.Parent = n
+	n.Children = append(n.Children, c)
+}
+
+// LastChild returns the last child of n, or nil
+func (n *Node) LastChild() *Node {
+	if len(n.Children) == 0 {
+		return nil
+	}
+	return n.Children[len(n.Children)-1]
+}
+
+// AttrIndex returns the index of the attribute key, or -1
+func (n *Node) AttrIndex(key string) int {
+	for i, a := range n.Attr {
+		if a.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// AttrVal returns the value of the attribute key
+func (n *Node) AttrVal(key string) (string, bool) {
+	if i := n.AttrIndex(key); i >= 0 {
+		return n.Attr[i].Val, true
+	}
+	return "", false
+}
+
+// Get returns the value of the attribute key, or ""
+func (n *Node) Get(key string) string {
+	val, _ := n.AttrVal(key)
+	return val
+}
+
+// SetAttr sets the attribute key to val
+func (n *Node) SetAttr(key, val string) {
+	if i := n.AttrIndex(key); i >= 0 {
+		n.Attr[i].Val = val
+		return
+	}
+	n.Attr = append(n.Attr, Attr{Key: key, Val: val})
+}
+
+// RemoveAttr deletes the attribute key
+func (n *Node) RemoveAttr(key string) {
+	if i := n.AttrIndex(key); i >= 0 {
+		n.Attr = append(n.Attr[:i], n.Attr[i+1:]...)
+	}
+}
+
+// Text returns the concatenated text of n and its descendants
+func (n *Node) Text() string {
+	if n.Type == TextNode {
+		return n.Data
+	}
+
+	var b strings.Builder
+	Walk(n, func(c *Node) bool {
+		if c.Type == TextNode {
+			b.WriteString(c.Data)
+		}
+		return true
+	})
+	return b.String()
+}
+
+// Walk calls fn for n and each descendant in document order. Returning false
+// skips the descendants of the current node.
+func Walk(n *Node, fn func(*Node) bool) {
+	if !fn(n) {
+		return
+	}
+	for _, c := range n.Children {
+		Walk(c, fn)
+	}
+}
+
+// FindAll returns every element below n with one of the given tag names
+func FindAll(n *Node, tags ...string) []*Node {
+	var found []*Node
+	Walk(n, func(c *Node) bool {
+		if c.Type == ElementNode {
+			for _, tag := range tags {
+				if c.Data == tag {
+					found = append(found, c)
+					break
+				}
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// Render writes the HTML serialization of n
+func Render(w io.Writer, n *Node) error {
+	var b strings.Builder
+	render(&b, n)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// String returns the HTML serialization of n's children, or of n itself when
+// it is not a document
+func (n *Node) String() string {
+	var b strings.Builder
+	render(&b, n)
+	return b.String()
+}
+
+func render(b *strings.Builder, n *Node) {
+	switch n.Type {
+	case DocumentNode:
+		for _, c := range n.Children {
+			render(b, c)
+		}
+	case TextNode:
+		if n.Parent != nil && (n.Parent.Data == "script" || n.Parent.Data == "style") {
+			b.WriteString(n.Data)
+		} else {
+			b.WriteString(html.EscapeString(n.Data))
+		}
+	case CommentNode:
+		b.WriteString("<!--")
+		b.WriteString(n.Data)
+		b.WriteString("-->")
+	case DoctypeNode:
+		b.WriteString("<!DOCTYPE ")
+		b.WriteString(n.Data)
+		b.WriteString(">")
+	case ElementNode:
+		b.WriteByte('<')
+		b.WriteString(n.Data)
+		for _, a := range n.Attr {
+			b.WriteByte(' ')
+			b.WriteString(a.Key)
+			b.WriteString(`="`)
+			b.WriteString(html.EscapeString(a.Val))
+			b.WriteByte('"')
+		}
+		b.WriteByte('>')
+		if voidElements[n.Data] {
+			return
+		}
+		for _, c := range n.Children {
+			render(b, c)
+		}
+		b.WriteString("</")
+		b.WriteString(n.Data)
+		b.WriteByte('>')
+	}
+}
+
+func isNameStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9') || c == '-' || c == ':' || c == '_'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// indexFold is a case-insensitive strings.Index for an ASCII needle
+func indexFold(s, needle string) int {
+	n := len(needle)
+	for i := 0; i+n <= len(s); i++ {
+		if strings.EqualFold(s[i:i+n], needle) {
+			return i
+		}
+	}
+	return -1
+}
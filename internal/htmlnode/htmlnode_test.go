@@ -0,0 +1,42 @@
+package htmlnode
+
+import "testing"
+
+func TestParseRender(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "lowercases names", input: `<P CLASS="a">t</P>`, want: `<p class="a">t</p>`},
+		{name: "quotes unquoted values", input: `<img src=x.png alt=a>`, want: `<img src="x.png" alt="a">`},
+		{name: "single quoted value with double quotes", input: `<p title='a" onclick="x()'>t</p>`, want: `<p title="a&#34; onclick=&#34;x()">t</p>`},
+		{name: "escapes markup in values", input: `<p title="&quot;>&lt;script>">t</p>`, want: `<p title="&#34;&gt;&lt;script&gt;">t</p>`},
+		{name: "first duplicate attribute wins", input: `<a href="/ok" HREF="javascript:alert(1)">x</a>`, want: `<a href="/ok">x</a>`},
+		{name: "escapes text", input: `<p>&lt;b&gt; &amp; <b>bold</b></p>`, want: `<p>&lt;b&gt; &amp; <b>bold</b></p>`},
+		{name: "script ends at first close tag", input: `<script>if (a < b) {}</SCRIPT><p>x</p>`, want: `<script>if (a < b) {}</script><p>x</p>`},
+		{name: "markup inside script is text", input: `<script>"<p onclick=x>"</script>`, want: `<script>"<p onclick=x>"</script>`},
+		{name: "textarea content is escaped", input: `<textarea></textarea><script>x</script>`, want: `<textarea></textarea><script>x</script>`},
+		{name: "textarea entities", input: `<textarea>&lt;/textarea&gt;<b></textarea>`, want: `<textarea>&lt;/textarea&gt;&lt;b&gt;</textarea>`},
+		{name: "closes unclosed elements", input: `<ul><li>a<li>b</ul><p>c`, want: `<ul><li>a</li><li>b</li></ul><p>c</p>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.input).String(); got != tt.want {
+				t.Errorf("Parse(%q).String()\n got %s\nwant %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributeEntities(t *testing.T) {
+	doc := Parse(`<a href="&#106;ava&#x09;script&colon;x">x</a>`)
+	links := FindAll(doc, "a")
+	if len(links) != 1 {
+		t.Fatalf("FindAll() = %d links, want 1", len(links))
+	}
+	if got := links[0].Get("href"); got != "java\tscript:x" {
+		t.Errorf("Get(href) = %q, want entities decoded", got)
+	}
+}
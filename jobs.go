@@ -0,0 +1,293 @@
+package semanticpen
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Job records a submitted article generation so that waiting for it can be
+// resumed after a process restart
+type Job struct {
+	ArticleID   string                  `json:"articleId"`
+	Request     *GenerateArticleRequest `json:"request,omitempty"`
+	Status      ArticleStatus           `json:"status,omitempty"`
+	Error       string                  `json:"error,omitempty"`
+	SubmittedAt time.Time               `json:"submittedAt,omitempty"`
+	UpdatedAt   time.Time               `json:"updatedAt,omitempty"`
+}
+
+// JobStore persists jobs. Implementations must be safe for concurrent use.
+type JobStore interface {
+	// Save records a job, keyed by ArticleID. Fields left at their zero value
+	// keep the previously recorded value.
+	Save(job Job) error
+	// Delete forgets a job
+	Delete(articleID string) error
+	// Load returns the latest state of every recorded job
+	Load() ([]Job, error)
+}
+
+// merge overlays the non-zero fields of update onto j
+func (j Job) merge(update Job) Job {
+	if update.Request != nil {
+		j.Request = update.Request
+	}
+	if update.Status != "" {
+		j.Status = update.Status
+	}
+	if update.Error != "" {
+		j.Error = update.Error
+	}
+	if !update.SubmittedAt.IsZero() {
+		j.SubmittedAt = update.SubmittedAt
+	}
+	if !update.UpdatedAt.IsZero() {
+		j.UpdatedAt = update.UpdatedAt
+	}
+	j.ArticleID = update.ArticleID
+	return j
+}
+
+// journalEntry is one line of a FileJobStore journal
+type journalEntry struct {
+	Job
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// FileJobStore is a JobStore backed by an append-only JSON-lines journal.
+// Every Save appends a line and syncs it to disk; the latest line for an
+// article wins when the journal is reloaded.
+type FileJobStore struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	jobs map[string]Job
+}
+
+// NewFileJobStore opens (or creates) the journal at path and replays it
+func NewFileJobStore(path string) (*FileJobStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create job journal directory: %w", err)
+	}
+
+	store := &FileJobStore{path: path, jobs: make(map[string]Job)}
+	if err := store.replay(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open job journal: %w", err)
+	}
+	store.file = file
+	return store, nil
+}
+
+// replay loads the journal into memory. Lines that cannot be decoded, such as
+// a partial line left by a crash, are skipped.
+func (s *FileJobStore) replay() error {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open job journal: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.ArticleID == "" {
+			continue
+		}
+		if entry.Deleted {
+			delete(s.jobs, entry.ArticleID)
+			continue
+		}
+		s.jobs[entry.ArticleID] = s.jobs[entry.ArticleID].merge(entry.Job)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read job journal: %w", err)
+	}
+	return nil
+}
+
+// Save appends the merged job state to the journal
+func (s *FileJobStore) Save(job Job) error {
+	if job.ArticleID == "" {
+		return &ValidationError{
+			Field:   "articleID",
+			Message: "article ID is required",
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	merged := s.jobs[job.ArticleID].merge(job)
+	if err := s.append(journalEntry{Job: merged}); err != nil {
+		return err
+	}
+	s.jobs[job.ArticleID] = merged
+	return nil
+}
+
+// Delete appends a tombstone for the job to the journal
+func (s *FileJobStore) Delete(articleID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[articleID]; !ok {
+		return nil
+	}
+	if err := s.append(journalEntry{Job: Job{ArticleID: articleID}, Deleted: true}); err != nil {
+		return err
+	}
+	delete(s.jobs, articleID)
+	return nil
+}
+
+// Load returns every recorded job, oldest submission first
+func (s *FileJobStore) Load() ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, k int) bool {
+		if jobs[i].SubmittedAt.Equal(jobs[k].SubmittedAt) {
+			return jobs[i].ArticleID < jobs[k].ArticleID
+		}
+		return jobs[i].SubmittedAt.Before(jobs[k].SubmittedAt)
+	})
+	return jobs, nil
+}
+
+// Compact rewrites the journal so that it holds a single line per job
+func (s *FileJobStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to compact job journal: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	encoder := json.NewEncoder(tmp)
+	for _, job := range s.jobs {
+		if err := encoder.Encode(journalEntry{Job: job}); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to compact job journal: %w", err)
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to compact job journal: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to compact job journal: %w", err)
+	}
+
+	// Open the compacted journal before renaming it into place, so that on
+	// any failure the store keeps appending to the old one
+	file, err := os.OpenFile(tmp.Name(), os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to reopen job journal: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to compact job journal: %w", err)
+	}
+
+	s.file.Close()
+	s.file = file
+	return nil
+}
+
+// Close closes the journal file
+func (s *FileJobStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// append writes one entry and syncs it to disk
+func (s *FileJobStore) append(entry journalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write job journal: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync job journal: %w", err)
+	}
+	return nil
+}
+
+// saveJob records a job in the configured store. Failures are logged rather
+// than returned so that bookkeeping never hides the outcome of an API call.
+func (c *Client) saveJob(job Job) {
+	if c.jobStore == nil {
+		return
+	}
+
+	job.UpdatedAt = time.Now()
+	if err := c.jobStore.Save(job); err != nil {
+		c.logger.Error("semanticpen failed to record job", "article_id", job.ArticleID, "error", err)
+	}
+}
+
+// deleteJob forgets a job in the configured store
+func (c *Client) deleteJob(articleID string) {
+	if c.jobStore == nil {
+		return
+	}
+
+	if err := c.jobStore.Delete(articleID); err != nil {
+		c.logger.Error("semanticpen failed to delete job", "article_id", articleID, "error", err)
+	}
+}
+
+// Resume reloads every unfinished job from the configured JobStore and
+// continues waiting for it. Jobs whose last-seen status is terminal are skipped.
+func (c *Client) Resume(ctx context.Context, options *WaitForArticlesOptions) ([]ArticleResult, error) {
+	if c.jobStore == nil {
+		return nil, &ValidationError{
+			Field:   "jobStore",
+			Message: "a JobStore must be configured to resume jobs",
+		}
+	}
+
+	jobs, err := c.jobStore.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jobs: %w", err)
+	}
+
+	var ids []string
+	for _, job := range jobs {
+		if !job.Status.IsTerminal() {
+			ids = append(ids, job.ArticleID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	c.logger.Info("semanticpen resuming jobs", "count", len(ids))
+	return c.WaitForArticles(ctx, ids, options)
+}
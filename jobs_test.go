@@ -0,0 +1,80 @@
+package semanticpen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileJobStoreCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.jsonl")
+	store, err := NewFileJobStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	for _, job := range []Job{{ArticleID: "a1", Status: StatusPending}, {ArticleID: "a1", Status: StatusProcessing}, {ArticleID: "a2"}} {
+		if err := store.Save(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Delete("a2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(Job{ArticleID: "a3"}); err != nil {
+		t.Fatalf("Save() after Compact() = %v", err)
+	}
+
+	reopened, err := NewFileJobStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	jobs, _ := reopened.Load()
+	if len(jobs) != 2 {
+		t.Fatalf("Load() = %+v, want a1 and a3", jobs)
+	}
+}
+
+func TestFileJobStoreCompactFailureKeepsJournal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "jobs.jsonl")
+	store, err := NewFileJobStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if err := store.Save(Job{ArticleID: "a1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Renaming onto a non-empty directory fails
+	blocked := filepath.Join(dir, "blocked")
+	if err := os.MkdirAll(filepath.Join(blocked, "child"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	store.path = blocked
+	if err := store.Compact(); err == nil {
+		t.Fatal("Compact() succeeded, want a rename error")
+	}
+	store.path = path
+
+	if err := store.Save(Job{ArticleID: "a2"}); err != nil {
+		t.Fatalf("Save() after failed Compact() = %v", err)
+	}
+	reopened, err := NewFileJobStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	if jobs, _ := reopened.Load(); len(jobs) != 2 {
+		t.Errorf("Load() = %+v, want a1 and a2", jobs)
+	}
+	if leftovers, _ := filepath.Glob(path + ".*"); len(leftovers) != 0 {
+		t.Errorf("temporary journals left behind: %v", leftovers)
+	}
+}
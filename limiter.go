@@ -0,0 +1,148 @@
+package semanticpen
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateLimit describes a token-bucket budget
+type RateLimit struct {
+	RequestsPerSecond float64 // Sustained request rate
+	Burst             int     // Requests allowed back-to-back; defaults to ceil(RequestsPerSecond)
+}
+
+// LimiterConfig configures the client-side rate limiter. Requests that match a
+// configured dedicated budget (Generate or Poll) draw only from that budget;
+// all others draw from Default. Requests with no applicable budget are unlimited.
+type LimiterConfig struct {
+	Default  *RateLimit // Budget for requests without a dedicated budget
+	Generate *RateLimit // Budget for POST /api/articles
+	Poll     *RateLimit // Budget for GET /api/articles/{id}, used while waiting for articles
+}
+
+// rateLimiter holds the token buckets shared by every goroutine using a Client
+type rateLimiter struct {
+	fallback *tokenBucket
+	generate *tokenBucket
+	poll     *tokenBucket
+}
+
+// newRateLimiter builds the buckets described by config, or returns nil when
+// no budget is configured
+func newRateLimiter(config *LimiterConfig) *rateLimiter {
+	if config == nil {
+		return nil
+	}
+
+	limiter := &rateLimiter{
+		fallback: newTokenBucket(config.Default),
+		generate: newTokenBucket(config.Generate),
+		poll:     newTokenBucket(config.Poll),
+	}
+	if limiter.fallback == nil && limiter.generate == nil && limiter.poll == nil {
+		return nil
+	}
+	return limiter
+}
+
+// wait blocks until the budget for the request allows it to proceed
+func (l *rateLimiter) wait(ctx context.Context, method, endpoint string) error {
+	if l == nil {
+		return nil
+	}
+
+	bucket := l.fallback
+	switch {
+	case l.generate != nil && method == http.MethodPost && endpoint == "/api/articles":
+		bucket = l.generate
+	case l.poll != nil && method == http.MethodGet && strings.HasPrefix(endpoint, "/api/articles/"):
+		bucket = l.poll
+	}
+	return bucket.wait(ctx)
+}
+
+// queueDepth returns the number of callers currently blocked on any budget
+func (l *rateLimiter) queueDepth() int {
+	if l == nil {
+		return 0
+	}
+	return l.fallback.queueDepth() + l.generate.queueDepth() + l.poll.queueDepth()
+}
+
+// tokenBucket is a context-aware token bucket safe for concurrent use
+type tokenBucket struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	tokens  float64
+	last    time.Time
+	waiting int64
+}
+
+// newTokenBucket creates a full bucket for the given budget, or nil when the
+// budget is unset
+func newTokenBucket(limit *RateLimit) *tokenBucket {
+	if limit == nil || limit.RequestsPerSecond <= 0 {
+		return nil
+	}
+
+	burst := float64(limit.Burst)
+	if burst <= 0 {
+		burst = math.Max(1, math.Ceil(limit.RequestsPerSecond))
+	}
+
+	return &tokenBucket{
+		rate:   limit.RequestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// wait reserves a token and sleeps until it becomes available. If ctx is
+// cancelled first, the reservation is returned to the bucket.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	deficit := -b.tokens
+	b.mu.Unlock()
+
+	if deficit <= 0 {
+		return nil
+	}
+
+	atomic.AddInt64(&b.waiting, 1)
+	defer atomic.AddInt64(&b.waiting, -1)
+
+	delay := time.Duration(deficit / b.rate * float64(time.Second))
+	if err := sleepContext(ctx, delay); err != nil {
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// queueDepth returns the number of callers currently waiting for a token
+func (b *tokenBucket) queueDepth() int {
+	if b == nil {
+		return 0
+	}
+	return int(atomic.LoadInt64(&b.waiting))
+}
@@ -0,0 +1,137 @@
+package semanticpen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketBurst(t *testing.T) {
+	bucket := newTokenBucket(&RateLimit{RequestsPerSecond: 1, Burst: 3})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := bucket.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("burst of 3 took %v, want no waiting", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := bucket.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("fourth wait error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	bucket := newTokenBucket(&RateLimit{RequestsPerSecond: 50})
+	if bucket.burst != 50 {
+		t.Errorf("default burst = %v, want ceil(RequestsPerSecond)", bucket.burst)
+	}
+	bucket.tokens = 0
+
+	start := time.Now()
+	if err := bucket.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("wait on an empty bucket took %v, want about 20ms", elapsed)
+	}
+}
+
+func TestTokenBucketRefundsOnCancel(t *testing.T) {
+	bucket := newTokenBucket(&RateLimit{RequestsPerSecond: 0.01, Burst: 1})
+	if err := bucket.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- bucket.wait(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for bucket.queueDepth() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("waiter never queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("wait() error = %v, want context.Canceled", err)
+	}
+	if depth := bucket.queueDepth(); depth != 0 {
+		t.Errorf("queueDepth() = %d after cancel, want 0", depth)
+	}
+
+	bucket.mu.Lock()
+	tokens := bucket.tokens
+	bucket.mu.Unlock()
+	if tokens < -0.01 {
+		t.Errorf("tokens = %v after cancel, want the reservation refunded", tokens)
+	}
+}
+
+func TestRateLimiterRouting(t *testing.T) {
+	limits := &LimiterConfig{
+		Default:  &RateLimit{RequestsPerSecond: 10},
+		Generate: &RateLimit{RequestsPerSecond: 10},
+		Poll:     &RateLimit{RequestsPerSecond: 10},
+	}
+
+	tests := []struct {
+		name     string
+		config   *LimiterConfig
+		method   string
+		endpoint string
+		want     string // fallback, generate or poll
+	}{
+		{name: "generate", config: limits, method: http.MethodPost, endpoint: "/api/articles", want: "generate"},
+		{name: "poll", config: limits, method: http.MethodGet, endpoint: "/api/articles/a1", want: "poll"},
+		{name: "list", config: limits, method: http.MethodGet, endpoint: "/api/articles?limit=10", want: "fallback"},
+		{name: "delete", config: limits, method: http.MethodDelete, endpoint: "/api/articles/a1", want: "fallback"},
+		{name: "generate without budget", config: &LimiterConfig{Default: limits.Default, Poll: limits.Poll}, method: http.MethodPost, endpoint: "/api/articles", want: "fallback"},
+		{name: "poll without budget", config: &LimiterConfig{Default: limits.Default}, method: http.MethodGet, endpoint: "/api/articles/a1", want: "fallback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := newRateLimiter(tt.config)
+			if err := limiter.wait(context.Background(), tt.method, tt.endpoint); err != nil {
+				t.Fatal(err)
+			}
+
+			used := map[string]*tokenBucket{"fallback": limiter.fallback, "generate": limiter.generate, "poll": limiter.poll}
+			for name, bucket := range used {
+				if bucket == nil {
+					continue
+				}
+				if drawn := bucket.tokens < bucket.burst-0.5; drawn != (name == tt.want) {
+					t.Errorf("%s bucket drawn = %v", name, drawn)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterUnconfigured(t *testing.T) {
+	for _, config := range []*LimiterConfig{nil, {}, {Default: &RateLimit{}}} {
+		limiter := newRateLimiter(config)
+		if limiter != nil {
+			t.Errorf("newRateLimiter(%+v) = %+v, want nil", config, limiter)
+		}
+		if err := limiter.wait(context.Background(), http.MethodGet, "/api/articles"); err != nil || limiter.queueDepth() != 0 {
+			t.Errorf("nil limiter: wait() = %v, queueDepth() = %d", err, limiter.queueDepth())
+		}
+	}
+
+	limiter := newRateLimiter(&LimiterConfig{Poll: &RateLimit{RequestsPerSecond: 1}})
+	if err := limiter.wait(context.Background(), http.MethodPost, "/api/articles"); err != nil {
+		t.Errorf("request without an applicable budget: wait() = %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package semanticpen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ListArticles retrieves one page of articles matching the given filters
+func (c *Client) ListArticles(options *ListArticlesOptions) (*ArticleList, error) {
+	return c.ListArticlesWithContext(context.Background(), options)
+}
+
+// ListArticlesWithContext is like ListArticles but honors ctx cancellation
+func (c *Client) ListArticlesWithContext(ctx context.Context, options *ListArticlesOptions) (*ArticleList, error) {
+	if options == nil {
+		options = &ListArticlesOptions{}
+	}
+
+	if options.Limit < 0 {
+		return nil, &ValidationError{
+			Field:   "limit",
+			Message: "limit must not be negative",
+		}
+	}
+	if !options.CreatedAfter.IsZero() && !options.CreatedBefore.IsZero() && options.CreatedAfter.After(options.CreatedBefore) {
+		return nil, &ValidationError{
+			Field:   "createdAfter",
+			Message: "createdAfter must be before createdBefore",
+		}
+	}
+
+	endpoint := "/api/articles"
+	if query := options.query().Encode(); query != "" {
+		endpoint += "?" + query
+	}
+
+	resp, err := c.makeRequest(ctx, "GET", endpoint, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	c.logBody("semanticpen response body", endpoint, body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseErrorResponse(resp, body)
+	}
+
+	var list ArticleList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.logger.Debug("semanticpen articles listed",
+		"count", len(list.Articles), "has_more", list.HasMore, "next_cursor", list.NextCursor)
+
+	return &list, nil
+}
+
+// query encodes the options as URL query parameters
+func (o *ListArticlesOptions) query() url.Values {
+	query := url.Values{}
+	if o.ProjectID != "" {
+		query.Set("projectId", o.ProjectID)
+	}
+	if o.Status != "" {
+		query.Set("status", string(o.Status))
+	}
+	if !o.CreatedAfter.IsZero() {
+		query.Set("createdAfter", o.CreatedAfter.UTC().Format(time.RFC3339))
+	}
+	if !o.CreatedBefore.IsZero() {
+		query.Set("createdBefore", o.CreatedBefore.UTC().Format(time.RFC3339))
+	}
+	if o.Keyword != "" {
+		query.Set("keyword", o.Keyword)
+	}
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	} else if o.Offset > 0 {
+		query.Set("offset", strconv.Itoa(o.Offset))
+	}
+	return query
+}
+
+// ArticleIterator walks every article matching a listing, fetching pages on
+// demand and following cursors or offsets transparently.
+//
+//	it := client.IterateArticles(&semanticpen.ListArticlesOptions{Status: semanticpen.StatusFailed})
+//	for it.Next(ctx) {
+//		article := it.Article()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ArticleIterator struct {
+	client  *Client
+	options ListArticlesOptions
+	page    []Article
+	index   int
+	article *Article
+	done    bool
+	err     error
+}
+
+// IterateArticles returns an iterator over all articles matching options
+func (c *Client) IterateArticles(options *ListArticlesOptions) *ArticleIterator {
+	it := &ArticleIterator{client: c}
+	if options != nil {
+		it.options = *options
+	}
+	return it
+}
+
+// Next advances to the next article, fetching the next page when needed.
+// It returns false when the listing is exhausted or an error occurred.
+func (it *ArticleIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.index >= len(it.page) {
+		if it.done {
+			it.article = nil
+			return false
+		}
+		if err := it.fetch(ctx); err != nil {
+			it.err = err
+			it.article = nil
+			return false
+		}
+	}
+
+	it.article = &it.page[it.index]
+	it.index++
+	return true
+}
+
+// Article returns the current article
+func (it *ArticleIterator) Article() *Article {
+	return it.article
+}
+
+// Err returns the first error encountered while iterating
+func (it *ArticleIterator) Err() error {
+	return it.err
+}
+
+// fetch loads the next page and works out how to request the one after it
+func (it *ArticleIterator) fetch(ctx context.Context) error {
+	list, err := it.client.ListArticlesWithContext(ctx, &it.options)
+	if err != nil {
+		return err
+	}
+
+	it.page = list.Articles
+	it.index = 0
+
+	switch {
+	case len(list.Articles) == 0:
+		it.done = true
+	case list.NextCursor != "":
+		if list.NextCursor == it.options.Cursor {
+			return fmt.Errorf("article listing returned the same cursor twice: %s", list.NextCursor)
+		}
+		it.options.Cursor = list.NextCursor
+	case it.options.Cursor == "" && (list.HasMore || it.options.Offset+len(list.Articles) < list.Total):
+		it.options.Offset += len(list.Articles)
+	default:
+		it.done = true
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package semanticpen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// pagedServer serves the article listing from pages keyed by the request's
+// cursor or offset query parameter, counting requests
+func pagedServer(t *testing.T, pages map[string]string) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Query().Get("status") != "failed" {
+			t.Errorf("request %s lost the status filter", r.URL)
+		}
+		key := r.URL.Query().Get("cursor") + r.URL.Query().Get("offset")
+		page, ok := pages[key]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, `{"message": "no page %q"}`, key)
+			return
+		}
+		fmt.Fprint(w, page)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// collect iterates to the end, returning the article IDs seen
+func collect(it *ArticleIterator) string {
+	var ids []string
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Article().ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestIterateArticles(t *testing.T) {
+	tests := []struct {
+		name  string
+		pages map[string]string
+	}{
+		{
+			name: "cursor",
+			pages: map[string]string{
+				"":   `{"articles": [{"id": "a1"}, {"id": "a2"}], "nextCursor": "c2", "hasMore": true}`,
+				"c2": `{"articles": [{"id": "a3"}], "hasMore": false}`,
+			},
+		},
+		{
+			name: "offset with total",
+			pages: map[string]string{
+				"":  `{"articles": [{"id": "a1"}, {"id": "a2"}], "total": 3}`,
+				"2": `{"articles": [{"id": "a3"}], "total": 3}`,
+			},
+		},
+		{
+			name: "offset with hasMore",
+			pages: map[string]string{
+				"":  `{"articles": [{"id": "a1"}, {"id": "a2"}], "hasMore": true}`,
+				"2": `{"articles": [{"id": "a3"}], "hasMore": true}`,
+				"3": `{"articles": []}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := pagedServer(t, tt.pages)
+			it := NewClient("key", &Config{BaseURL: server.URL}).IterateArticles(&ListArticlesOptions{Status: StatusFailed})
+
+			if got := collect(it); got != "a1,a2,a3" || it.Err() != nil {
+				t.Errorf("iterated %q, error %v; want a1,a2,a3", got, it.Err())
+			}
+			if int(*requests) != len(tt.pages) {
+				t.Errorf("%d requests, want one per page (%d)", *requests, len(tt.pages))
+			}
+			if it.Next(context.Background()) || it.Article() != nil || int(*requests) != len(tt.pages) {
+				t.Error("Next() after the end should return false without fetching")
+			}
+		})
+	}
+}
+
+func TestIterateArticlesStopsOnError(t *testing.T) {
+	tests := []struct {
+		name    string
+		pages   map[string]string
+		wantErr func(error) bool
+	}{
+		{
+			name: "server error",
+			pages: map[string]string{
+				"": `{"articles": [{"id": "a1"}, {"id": "a2"}], "nextCursor": "c2"}`,
+			},
+			wantErr: func(err error) bool {
+				var apiErr *APIError
+				return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusInternalServerError
+			},
+		},
+		{
+			name: "repeated cursor",
+			pages: map[string]string{
+				"":   `{"articles": [{"id": "a1"}, {"id": "a2"}], "nextCursor": "c2"}`,
+				"c2": `{"articles": [{"id": "a3"}], "nextCursor": "c2"}`,
+			},
+			wantErr: func(err error) bool {
+				return err != nil && strings.Contains(err.Error(), "same cursor twice")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := pagedServer(t, tt.pages)
+			it := NewClient("key", &Config{BaseURL: server.URL}).IterateArticles(&ListArticlesOptions{Status: StatusFailed})
+
+			got := collect(it)
+			if !strings.HasPrefix(got, "a1,a2") || !tt.wantErr(it.Err()) {
+				t.Errorf("iterated %q, error %v", got, it.Err())
+			}
+			sent := *requests
+			if it.Next(context.Background()) || *requests != sent {
+				t.Error("Next() after an error should return false without fetching")
+			}
+		})
+	}
+}
+
+func TestIterateArticlesEarlyBreak(t *testing.T) {
+	server, requests := pagedServer(t, map[string]string{
+		"":   `{"articles": [{"id": "a1"}, {"id": "a2"}], "nextCursor": "c2"}`,
+		"c2": `{"articles": [{"id": "a3"}]}`,
+	})
+	it := NewClient("key", &Config{BaseURL: server.URL}).IterateArticles(&ListArticlesOptions{Status: StatusFailed})
+
+	for it.Next(context.Background()) {
+		if it.Article().ID == "a2" {
+			break
+		}
+	}
+	if *requests != 1 || it.Err() != nil {
+		t.Errorf("%d requests, error %v; want only the first page fetched", *requests, it.Err())
+	}
+}
+
+func TestIterateArticlesValidation(t *testing.T) {
+	it := NewClient("key", nil).IterateArticles(&ListArticlesOptions{Limit: -1})
+	var validation *ValidationError
+	if it.Next(context.Background()) || !errors.As(it.Err(), &validation) || validation.Field != "limit" {
+		t.Errorf("Err() = %v, want a limit ValidationError", it.Err())
+	}
+}
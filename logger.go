@@ -0,0 +1,191 @@
+package semanticpen
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxLogBodyBytes is the default truncation limit for logged bodies
+const DefaultMaxLogBodyBytes = 1024
+
+// Logger is the structured logger used by the client. Arguments are
+// alternating key/value pairs. *slog.Logger satisfies this interface, so on
+// Go 1.21+ a slog logger can be passed to Config.Logger directly.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// LogOptions controls what the client includes in its logs
+type LogOptions struct {
+	LogBodies    bool // Log request and response bodies at debug level
+	MaxBodyBytes int  // Truncate logged bodies to this many bytes; defaults to DefaultMaxLogBodyBytes
+	ShowContent  bool // Log article_html and article_json verbatim instead of redacting them
+}
+
+// redactedFields are JSON keys whose values are replaced in logged bodies
+// unless LogOptions.ShowContent is set
+var redactedFields = []string{"article_html", "article_json"}
+
+// NewStdLogger returns a Logger that writes logfmt-style lines
+// (level=DEBUG msg="..." key=value) to the given standard library logger
+func NewStdLogger(l *log.Logger) Logger {
+	return &stdLogger{logger: l}
+}
+
+// stdLogger adapts *log.Logger to the Logger interface
+type stdLogger struct {
+	logger *log.Logger
+}
+
+func (s *stdLogger) Debug(msg string, args ...interface{}) { s.output("DEBUG", msg, args) }
+func (s *stdLogger) Info(msg string, args ...interface{})  { s.output("INFO", msg, args) }
+func (s *stdLogger) Warn(msg string, args ...interface{})  { s.output("WARN", msg, args) }
+func (s *stdLogger) Error(msg string, args ...interface{}) { s.output("ERROR", msg, args) }
+
+func (s *stdLogger) output(level, msg string, args []interface{}) {
+	var b strings.Builder
+	b.WriteString("level=")
+	b.WriteString(level)
+	b.WriteString(" msg=")
+	b.WriteString(strconv.Quote(msg))
+
+	for i := 0; i < len(args); i += 2 {
+		key, value := fmt.Sprint(args[i]), interface{}("!MISSING")
+		if i+1 < len(args) {
+			value = args[i+1]
+		}
+
+		text := fmt.Sprint(value)
+		if text == "" || strings.ContainsAny(text, " \t\n\"=") {
+			text = strconv.Quote(text)
+		}
+
+		b.WriteString(" ")
+		b.WriteString(key)
+		b.WriteString("=")
+		b.WriteString(text)
+	}
+
+	s.logger.Print(b.String())
+}
+
+// newLogger picks the logger for a client configuration
+func newLogger(config *Config) Logger {
+	if config.Logger != nil {
+		return config.Logger
+	}
+	if config.Debug {
+		return NewStdLogger(log.New(os.Stderr, "", log.LstdFlags))
+	}
+	return nopLogger{}
+}
+
+// newLogOptions returns the log options for a client configuration with
+// defaults filled in. Debug mode logs bodies unless LogOptions says otherwise.
+func newLogOptions(config *Config) LogOptions {
+	options := LogOptions{LogBodies: config.Debug}
+	if config.LogOptions != nil {
+		options = *config.LogOptions
+	}
+	if options.MaxBodyBytes <= 0 {
+		options.MaxBodyBytes = DefaultMaxLogBodyBytes
+	}
+	return options
+}
+
+// nopLogger discards everything
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+
+// logBody logs a request or response body at debug level, with API keys and
+// article content redacted and the result truncated
+func (c *Client) logBody(msg, endpoint string, body []byte) {
+	if !c.logOptions.LogBodies || len(body) == 0 {
+		return
+	}
+	c.logger.Debug(msg, "endpoint", endpoint, "body", c.redactBody(body))
+}
+
+// redactBody prepares a body for logging
+func (c *Client) redactBody(body []byte) string {
+	text := string(body)
+
+	if !c.logOptions.ShowContent {
+		if out, ok := redactJSON(body); ok {
+			text = string(out)
+		}
+	}
+
+	if c.apiKey != "" {
+		text = strings.ReplaceAll(text, c.apiKey, "[redacted]")
+	}
+
+	if limit := c.logOptions.MaxBodyBytes; len(text) > limit {
+		text = fmt.Sprintf("%s...[truncated %d bytes]", text[:limit], len(text)-limit)
+	}
+	return text
+}
+
+// redactJSON replaces the values of redactedFields wherever they appear in a
+// JSON document, including inside nested objects and arrays such as a list of
+// articles. It reports whether anything was redacted.
+func redactJSON(data []byte) (json.RawMessage, bool) {
+	var redacted bool
+	var out interface{}
+
+	switch trimmed := strings.TrimSpace(string(data)); {
+	case strings.HasPrefix(trimmed, "{"):
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, false
+		}
+		for key, value := range fields {
+			if isRedactedField(key) {
+				fields[key] = json.RawMessage(strconv.Quote(fmt.Sprintf("[redacted %d bytes]", len(value))))
+				redacted = true
+			} else if value, ok := redactJSON(value); ok {
+				fields[key] = value
+				redacted = true
+			}
+		}
+		out = fields
+	case strings.HasPrefix(trimmed, "["):
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, false
+		}
+		for i, item := range items {
+			if item, ok := redactJSON(item); ok {
+				items[i] = item
+				redacted = true
+			}
+		}
+		out = items
+	}
+
+	if !redacted {
+		return nil, false
+	}
+	encoded, err := json.Marshal(out)
+	return encoded, err == nil
+}
+
+func isRedactedField(key string) bool {
+	for _, field := range redactedFields {
+		if key == field {
+			return true
+		}
+	}
+	return false
+}
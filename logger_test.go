@@ -0,0 +1,71 @@
+package semanticpen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		options LogOptions
+		body    string
+		want    string
+	}{
+		{name: "top-level content", body: `{"id":"a1","article_html":"<p>secret</p>"}`, want: `{"article_html":"[redacted 15 bytes]","id":"a1"}`},
+		{name: "nested object", body: `{"data":{"article_json":{"title":"secret"}}}`, want: `{"data":{"article_json":"[redacted 18 bytes]"}}`},
+		{name: "top-level array", body: `[{"article_html":"secret"},{"id":"a2"}]`, want: `[{"article_html":"[redacted 8 bytes]"},{"id":"a2"}]`},
+		{name: "nothing to redact", body: `{"articles": [{"id": "a1"}]}`, want: `{"articles": [{"id": "a1"}]}`},
+		{name: "not json", body: `article_html: secret`, want: `article_html: secret`},
+		{name: "show content", options: LogOptions{ShowContent: true}, body: `{"article_html":"secret"}`, want: `{"article_html":"secret"}`},
+		{name: "api key", body: `{"key":"sk-test"}`, want: `{"key":"[redacted]"}`},
+		{name: "truncated", options: LogOptions{MaxBodyBytes: 5}, body: `"abcdefgh"`, want: `"abcd...[truncated 5 bytes]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("sk-test", &Config{LogOptions: &tt.options})
+			if got := client.redactBody([]byte(tt.body)); got != tt.want {
+				t.Errorf("redactBody() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListArticlesLogRedactsContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"articles": [
+			{"id": "a1", "article_html": "<p>first secret</p>", "article_json": {"title": "first secret"}},
+			{"id": "a2", "article_html": "<p>second secret</p>"}
+		], "hasMore": false}`)
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	client := NewClient("key", &Config{
+		BaseURL:    server.URL,
+		Logger:     NewStdLogger(log.New(&out, "", 0)),
+		LogOptions: &LogOptions{LogBodies: true, MaxBodyBytes: 1 << 20},
+	})
+	list, err := client.ListArticlesWithContext(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Articles) != 2 || list.Articles[0].ArticleHTML == "" {
+		t.Fatalf("ListArticles() = %+v, want the content itself untouched", list.Articles)
+	}
+
+	logged := out.String()
+	if strings.Contains(logged, "secret") {
+		t.Errorf("article content was logged:\n%s", logged)
+	}
+	if strings.Count(logged, "[redacted") != 3 {
+		t.Errorf("want three redacted fields in:\n%s", logged)
+	}
+}
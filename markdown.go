@@ -0,0 +1,467 @@
+package semanticpen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pushkarsingh32/semanticpen-go-sdk/internal/htmlnode"
+)
+
+// LinkStyle selects how links are written in Markdown
+type LinkStyle string
+
+const (
+	LinkInline    LinkStyle = "inline"    // [text](url)
+	LinkReference LinkStyle = "reference" // [text][1] with definitions at the end
+)
+
+// MarkdownOptions configures HTML to Markdown conversion
+type MarkdownOptions struct {
+	HeadingOffset int       // Added to every heading level, e.g. 1 turns <h1> into ##; levels are clamped to 1-6
+	LinkStyle     LinkStyle // Defaults to LinkInline
+}
+
+// Markdown converts the article's HTML into GitHub-flavored Markdown
+func (a *Article) Markdown(options *MarkdownOptions) (string, error) {
+	if a.ArticleHTML == "" {
+		return "", fmt.Errorf("article has no HTML content")
+	}
+	return HTMLToMarkdown(a.ArticleHTML, options), nil
+}
+
+// HTMLToMarkdown converts an HTML fragment into GitHub-flavored Markdown,
+// handling headings, paragraphs, emphasis, links, images, lists, tables, code
+// blocks and blockquotes
+func HTMLToMarkdown(source string, options *MarkdownOptions) string {
+	m := &markdownWriter{linkStyle: LinkInline}
+	if options != nil {
+		m.headingOffset = options.HeadingOffset
+		if options.LinkStyle != "" {
+			m.linkStyle = options.LinkStyle
+		}
+	}
+
+	out := m.blocks(htmlnode.Parse(source).Children, false)
+
+	if len(m.references) > 0 {
+		var refs strings.Builder
+		for i, ref := range m.references {
+			fmt.Fprintf(&refs, "[%d]: %s", i+1, ref.url)
+			if ref.title != "" {
+				fmt.Fprintf(&refs, " %q", ref.title)
+			}
+			refs.WriteByte('\n')
+		}
+		out = strings.TrimRight(out, "\n") + "\n\n" + refs.String()
+	}
+
+	return strings.TrimSpace(out) + "\n"
+}
+
+// markdownBlockElements are rendered as blocks rather than inline content
+var markdownBlockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "body": true,
+	"dd": true, "div": true, "dl": true, "dt": true, "figcaption": true, "figure": true,
+	"footer": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "html": true, "li": true, "main": true, "nav": true,
+	"ol": true, "p": true, "pre": true, "section": true, "table": true, "ul": true,
+}
+
+// markdownSkipped elements produce no output
+var markdownSkipped = map[string]bool{
+	"head": true, "script": true, "style": true, "noscript": true, "template": true,
+	"iframe": true, "object": true, "svg": true, "form": true, "button": true,
+}
+
+var (
+	whitespaceRun   = regexp.MustCompile(`\s+`)
+	markdownSpecial = regexp.MustCompile("([\\\\`*_\\[\\]])")
+	// markdownEntities keeps decoded text from turning into live HTML
+	markdownEntities = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	lineStartMarker  = regexp.MustCompile(`(?m)^(\s*)([#>+\-]|\d+\.)(\s)`)
+)
+
+type markdownReference struct {
+	url   string
+	title string
+}
+
+// markdownWriter holds conversion state across one document
+type markdownWriter struct {
+	headingOffset int
+	linkStyle     LinkStyle
+	references    []markdownReference
+	inCell        bool // Rendering a table cell, where line breaks must stay inline
+}
+
+// blocks renders a sequence of nodes, grouping inline runs into paragraphs.
+// Tight output (used inside list items) separates blocks with a single newline.
+func (m *markdownWriter) blocks(nodes []*htmlnode.Node, tight bool) string {
+	var parts []string
+	var inline []*htmlnode.Node
+
+	flush := func() {
+		if len(inline) == 0 {
+			return
+		}
+		if text := strings.TrimSpace(m.inlines(inline)); text != "" {
+			parts = append(parts, escapeLineStart(text))
+		}
+		inline = nil
+	}
+
+	for _, n := range nodes {
+		if n.Type == htmlnode.ElementNode && markdownSkipped[n.Data] {
+			continue
+		}
+		if n.Type != htmlnode.ElementNode || !markdownBlockElements[n.Data] {
+			if n.Type == htmlnode.TextNode || n.Type == htmlnode.ElementNode {
+				inline = append(inline, n)
+			}
+			continue
+		}
+
+		flush()
+		if block := m.block(n); strings.TrimSpace(block) != "" {
+			parts = append(parts, block)
+		}
+	}
+	flush()
+
+	separator := "\n\n"
+	if tight {
+		separator = "\n"
+	}
+	return strings.Join(parts, separator)
+}
+
+// block renders a single block-level element
+func (m *markdownWriter) block(n *htmlnode.Node) string {
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level := int(n.Data[1]-'0') + m.headingOffset
+		if level < 1 {
+			level = 1
+		}
+		if level > 6 {
+			level = 6
+		}
+		return strings.Repeat("#", level) + " " + strings.TrimSpace(m.inlines(n.Children))
+	case "p", "dt", "figcaption":
+		return escapeLineStart(strings.TrimSpace(m.inlines(n.Children)))
+	case "hr":
+		return "---"
+	case "ul", "ol":
+		return m.list(n)
+	case "pre":
+		return m.codeBlock(n)
+	case "blockquote":
+		inner := m.blocks(n.Children, false)
+		lines := strings.Split(inner, "\n")
+		for i, line := range lines {
+			if line == "" {
+				lines[i] = ">"
+			} else {
+				lines[i] = "> " + line
+			}
+		}
+		return strings.Join(lines, "\n")
+	case "table":
+		return m.table(n)
+	case "dd":
+		return indent(m.blocks(n.Children, false), ": ", "  ")
+	}
+	return m.blocks(n.Children, false)
+}
+
+// list renders ul/ol items, indenting continuation lines under the marker
+func (m *markdownWriter) list(n *htmlnode.Node) string {
+	ordered := n.Data == "ol"
+	number := 1
+	if start, err := strconv.Atoi(n.Get("start")); err == nil && ordered {
+		number = start
+	}
+
+	var items []string
+	for _, c := range n.Children {
+		if c.Type != htmlnode.ElementNode || c.Data != "li" {
+			continue
+		}
+
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+
+		content := m.blocks(c.Children, true)
+		items = append(items, indent(content, marker, strings.Repeat(" ", len(marker))))
+	}
+	return strings.Join(items, "\n")
+}
+
+// codeBlock renders <pre> as a fenced code block, taking the language from a
+// language-* or lang-* class on the <pre> or its <code>
+func (m *markdownWriter) codeBlock(n *htmlnode.Node) string {
+	code := n.Text()
+	language := codeLanguage(n)
+	for _, c := range n.Children {
+		if c.Type == htmlnode.ElementNode && c.Data == "code" {
+			if lang := codeLanguage(c); lang != "" {
+				language = lang
+			}
+		}
+	}
+
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + language + "\n" + strings.TrimRight(code, "\n") + "\n" + fence
+}
+
+func codeLanguage(n *htmlnode.Node) string {
+	for _, class := range strings.Fields(n.Get("class")) {
+		for _, prefix := range []string{"language-", "lang-"} {
+			if strings.HasPrefix(class, prefix) {
+				return strings.TrimPrefix(class, prefix)
+			}
+		}
+	}
+	return ""
+}
+
+// table renders a GFM table; the first row becomes the header
+func (m *markdownWriter) table(n *htmlnode.Node) string {
+	var rows [][]string
+	var aligns []string
+
+	for _, tr := range htmlnode.FindAll(n, "tr") {
+		var row []string
+		for _, cell := range tr.Children {
+			if cell.Type != htmlnode.ElementNode || (cell.Data != "td" && cell.Data != "th") {
+				continue
+			}
+			m.inCell = true
+			text := strings.TrimSpace(m.inlines(cell.Children))
+			m.inCell = false
+			text = strings.ReplaceAll(strings.ReplaceAll(text, "\n", " "), "|", `\|`)
+			row = append(row, text)
+
+			if len(rows) == 0 {
+				align := cell.Get("align")
+				if style := strings.ToLower(cell.Get("style")); strings.Contains(style, "text-align") {
+					for _, a := range []string{"center", "right", "left"} {
+						if strings.Contains(style, a) {
+							align = a
+						}
+					}
+				}
+				aligns = append(aligns, align)
+			}
+		}
+		if len(row) > 0 {
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+
+	columns := 0
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	var b strings.Builder
+	writeRow := func(row []string) {
+		b.WriteString("|")
+		for i := 0; i < columns; i++ {
+			cell := ""
+			if i < len(row) {
+				cell = row[i]
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(rows[0])
+	b.WriteString("|")
+	for i := 0; i < columns; i++ {
+		align := ""
+		if i < len(aligns) {
+			align = aligns[i]
+		}
+		switch align {
+		case "center":
+			b.WriteString(" :---: |")
+		case "right":
+			b.WriteString(" ---: |")
+		case "left":
+			b.WriteString(" :--- |")
+		default:
+			b.WriteString(" --- |")
+		}
+	}
+	b.WriteString("\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// inlines renders inline content, collapsing whitespace
+func (m *markdownWriter) inlines(nodes []*htmlnode.Node) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		b.WriteString(m.inline(n))
+	}
+	return b.String()
+}
+
+func (m *markdownWriter) inline(n *htmlnode.Node) string {
+	if n.Type == htmlnode.TextNode {
+		return escapeMarkdown(whitespaceRun.ReplaceAllString(n.Data, " "))
+	}
+	if n.Type != htmlnode.ElementNode || markdownSkipped[n.Data] {
+		return ""
+	}
+
+	switch n.Data {
+	case "br":
+		if m.inCell {
+			// GFM table cells cannot span lines; renderers accept a literal <br>
+			return "<br>"
+		}
+		return "\\\n"
+	case "strong", "b":
+		return wrapInline(m.inlines(n.Children), "**")
+	case "em", "i":
+		return wrapInline(m.inlines(n.Children), "*")
+	case "del", "s", "strike":
+		return wrapInline(m.inlines(n.Children), "~~")
+	case "code":
+		code := whitespaceRun.ReplaceAllString(n.Text(), " ")
+		fence := "`"
+		for strings.Contains(code, fence) {
+			fence += "`"
+		}
+		if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+			code = " " + code + " "
+		}
+		return fence + code + fence
+	case "a":
+		return m.link(n)
+	case "img":
+		return m.image(n)
+	}
+
+	if markdownBlockElements[n.Data] {
+		return " " + m.blocks(n.Children, true) + " "
+	}
+	return m.inlines(n.Children)
+}
+
+// link renders an anchor in the configured link style
+func (m *markdownWriter) link(n *htmlnode.Node) string {
+	text := strings.TrimSpace(m.inlines(n.Children))
+	href := n.Get("href")
+	if href == "" {
+		return text
+	}
+	if text == "" {
+		text = href
+	}
+
+	title := n.Get("title")
+	if m.linkStyle == LinkReference {
+		return "[" + text + "][" + strconv.Itoa(m.reference(href, title)) + "]"
+	}
+	return "[" + text + "](" + markdownURL(href) + markdownTitle(title) + ")"
+}
+
+// image renders an <img> as an inline image
+func (m *markdownWriter) image(n *htmlnode.Node) string {
+	src := n.Get("src")
+	if src == "" {
+		return ""
+	}
+	alt := escapeMarkdown(n.Get("alt"))
+	return "![" + alt + "](" + markdownURL(src) + markdownTitle(n.Get("title")) + ")"
+}
+
+// reference returns the 1-based number of a link definition, adding it if new
+func (m *markdownWriter) reference(url, title string) int {
+	for i, ref := range m.references {
+		if ref.url == url && ref.title == title {
+			return i + 1
+		}
+	}
+	m.references = append(m.references, markdownReference{url: url, title: title})
+	return len(m.references)
+}
+
+// wrapInline surrounds text with a delimiter, keeping surrounding spaces
+// outside so the emphasis stays valid
+func wrapInline(text, delimiter string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	leading := text[:len(text)-len(strings.TrimLeft(text, " "))]
+	trailing := text[len(strings.TrimRight(text, " ")):]
+	return leading + delimiter + trimmed + delimiter + trailing
+}
+
+func markdownURL(url string) string {
+	if strings.ContainsAny(url, " ()") {
+		return "<" + url + ">"
+	}
+	return url
+}
+
+func markdownTitle(title string) string {
+	if title == "" {
+		return ""
+	}
+	return " " + strconv.Quote(title)
+}
+
+// escapeLineStart escapes characters that would otherwise start a heading,
+// quote or list at the beginning of a line
+// escapeMarkdown escapes text so it renders literally: HTML metacharacters
+// become entities and Markdown punctuation is backslash-escaped
+func escapeMarkdown(text string) string {
+	return markdownSpecial.ReplaceAllString(markdownEntities.Replace(text), `\$1`)
+}
+
+func escapeLineStart(text string) string {
+	return lineStartMarker.ReplaceAllStringFunc(text, func(match string) string {
+		sub := lineStartMarker.FindStringSubmatch(match)
+		marker := sub[2]
+		if strings.HasSuffix(marker, ".") {
+			return sub[1] + marker[:len(marker)-1] + `\.` + sub[3]
+		}
+		return sub[1] + `\` + marker + sub[3]
+	})
+}
+
+// indent prefixes the first line of text with first and the remaining
+// non-empty lines with rest
+func indent(text, first, rest string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			lines[i] = first + line
+		case line != "":
+			lines[i] = rest + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
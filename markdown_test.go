@@ -0,0 +1,74 @@
+package semanticpen
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata with the current output")
+
+func TestHTMLToMarkdownGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options *MarkdownOptions
+	}{
+		{name: "article", input: "article.html"},
+		{name: "article_reference", input: "article.html", options: &MarkdownOptions{HeadingOffset: 1, LinkStyle: LinkReference}},
+		{name: "listicle", input: "listicle.html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", "markdown", tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := HTMLToMarkdown(string(input), tt.options)
+
+			golden := filepath.Join("testdata", "markdown", tt.name+".md")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run go test -run TestHTMLToMarkdownGolden -update to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("output does not match %s\n--- got ---\n%s\n--- want ---\n%s", golden, got, want)
+			}
+		})
+	}
+}
+
+func TestHTMLToMarkdownInline(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"line break", "<p>One<br>Two</p>", "One\\\nTwo\n"},
+		{"line break in table cell", "<table><tr><th>A</th></tr><tr><td>2<br>3</td></tr></table>", "| A |\n| --- |\n| 2<br>3 |\n"},
+		{"escaped specials", "<p>a*b_c [d]</p>", "a\\*b\\_c \\[d\\]\n"},
+		{"code with backticks", "<p><code>a`b</code></p>", "``a`b``\n"},
+		{"skipped script", "<p>x</p><script>alert(1)</script>", "x\n"},
+		{"escaped html in text", "<p>Use &lt;img src=x onerror=alert(1)&gt; &amp;</p>", "Use &lt;img src=x onerror=alert(1)&gt; &amp;\n"},
+		{"escaped entity in text", "<p>&amp;lt;b&amp;gt;</p>", "&amp;lt;b&amp;gt;\n"},
+		{"escaped html in link text", `<p><a href="/x">&lt;script&gt;</a></p>`, "[&lt;script&gt;](/x)\n"},
+		{"escaped html in table cell", "<table><tr><th>A</th></tr><tr><td>&lt;b&gt;</td></tr></table>", "| A |\n| --- |\n| &lt;b&gt; |\n"},
+		{"escaped html in alt text", `<img src="a.png" alt="&lt;svg onload=x&gt; &amp; *b*">`, "![&lt;svg onload=x&gt; &amp; \\*b\\*](a.png)\n"},
+		{"quote marker is not a blockquote", "<p>&gt; not a quote</p>", "&gt; not a quote\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTMLToMarkdown(tt.html, nil); got != tt.want {
+				t.Errorf("HTMLToMarkdown(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
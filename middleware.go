@@ -0,0 +1,49 @@
+package semanticpen
+
+import "net/http"
+
+// Middleware wraps a RoundTripper to add behavior such as authentication,
+// logging, metrics or fault injection around every request the client makes
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts an ordinary function to the http.RoundTripper interface
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req)
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// chainMiddleware wraps base with the given middleware. The first middleware is
+// the outermost layer and sees each request first.
+func chainMiddleware(base http.RoundTripper, middleware ...Middleware) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] != nil {
+			base = middleware[i](base)
+		}
+	}
+	return base
+}
+
+// newHTTPClient returns the http.Client used by the SDK: a copy of the
+// configured client (or a new one) with the middleware chain installed
+func newHTTPClient(config *Config) *http.Client {
+	httpClient := &http.Client{}
+	if config.HTTPClient != nil {
+		*httpClient = *config.HTTPClient
+	}
+
+	if httpClient.Timeout == 0 {
+		httpClient.Timeout = config.Timeout
+	}
+
+	if len(config.Middleware) > 0 {
+		httpClient.Transport = chainMiddleware(httpClient.Transport, config.Middleware...)
+	}
+
+	return httpClient
+}
@@ -0,0 +1,128 @@
+package semanticpen
+
+import (
+	"math"
+	"time"
+)
+
+// PollState describes the wait so far, for deciding when to poll next
+type PollState struct {
+	Attempt int           // Status checks made so far
+	Elapsed time.Duration // Time since waiting started
+	Article *Article      // Latest article snapshot
+}
+
+// PollStrategy decides how long WaitForArticle sleeps before the next status check
+type PollStrategy interface {
+	NextInterval(state PollState) time.Duration
+}
+
+// PollStrategyFunc adapts an ordinary function to the PollStrategy interface
+type PollStrategyFunc func(state PollState) time.Duration
+
+// NextInterval calls f(state)
+func (f PollStrategyFunc) NextInterval(state PollState) time.Duration {
+	return f(state)
+}
+
+// FixedPoll polls at a constant interval
+type FixedPoll struct {
+	Interval time.Duration
+}
+
+// NextInterval returns the fixed interval
+func (p FixedPoll) NextInterval(state PollState) time.Duration {
+	if p.Interval <= 0 {
+		return DefaultWaitInterval
+	}
+	return p.Interval
+}
+
+// ExponentialPoll starts polling quickly and backs off geometrically up to a cap
+type ExponentialPoll struct {
+	Initial    time.Duration // First interval; defaults to 1s
+	Max        time.Duration // Upper bound; defaults to 30s
+	Multiplier float64       // Growth factor per attempt; defaults to 1.5
+}
+
+// NextInterval returns Initial * Multiplier^(attempt-1), capped at Max
+func (p ExponentialPoll) NextInterval(state PollState) time.Duration {
+	initial, maxInterval, multiplier := p.Initial, p.Max, p.Multiplier
+	if initial <= 0 {
+		initial = time.Second
+	}
+	if maxInterval <= 0 {
+		maxInterval = 30 * time.Second
+	}
+	if multiplier <= 1 {
+		multiplier = 1.5
+	}
+
+	exponent := float64(state.Attempt - 1)
+	if exponent < 0 {
+		exponent = 0
+	}
+	interval := float64(initial) * math.Pow(multiplier, exponent)
+	if interval > float64(maxInterval) {
+		return maxInterval
+	}
+	return time.Duration(interval)
+}
+
+// ProgressPoll uses Article.Progress to estimate the remaining generation time
+// and polls again after a fraction of it, so checks are sparse while a long
+// generation runs and frequent as it nears completion
+type ProgressPoll struct {
+	Min      time.Duration // Shortest interval; defaults to 2s
+	Max      time.Duration // Longest interval; defaults to 30s
+	Fraction float64       // Share of the estimated remaining time to wait; defaults to 0.5
+}
+
+// NextInterval returns Fraction of the estimated remaining time, clamped to [Min, Max]
+func (p ProgressPoll) NextInterval(state PollState) time.Duration {
+	minInterval, maxInterval, fraction := p.Min, p.Max, p.Fraction
+	if minInterval <= 0 {
+		minInterval = 2 * time.Second
+	}
+	if maxInterval <= 0 {
+		maxInterval = 30 * time.Second
+	}
+	if fraction <= 0 || fraction > 1 {
+		fraction = 0.5
+	}
+
+	remaining, ok := estimateRemaining(state.Article, state.Elapsed)
+	if !ok {
+		return minInterval
+	}
+
+	interval := time.Duration(float64(remaining) * fraction)
+	if interval < minInterval {
+		return minInterval
+	}
+	if interval > maxInterval {
+		return maxInterval
+	}
+	return interval
+}
+
+// estimateRemaining extrapolates the time left from the progress made so far.
+// Progress is measured from the article's creation time when the API reports
+// it, otherwise from when waiting started.
+func estimateRemaining(article *Article, elapsed time.Duration) (time.Duration, bool) {
+	if article == nil || article.Progress <= 0 || article.Progress >= 100 {
+		return 0, false
+	}
+
+	if !article.CreatedAt.IsZero() {
+		if sinceCreated := time.Since(article.CreatedAt); sinceCreated > elapsed {
+			elapsed = sinceCreated
+		}
+	}
+	if elapsed <= 0 {
+		return 0, false
+	}
+
+	perPercent := float64(elapsed) / float64(article.Progress)
+	return time.Duration(perPercent * float64(100-article.Progress)), true
+}
@@ -0,0 +1,110 @@
+package semanticpen
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExponentialPoll(t *testing.T) {
+	tests := []struct {
+		name    string
+		poll    ExponentialPoll
+		attempt int
+		want    time.Duration
+	}{
+		{name: "defaults first attempt", attempt: 1, want: time.Second},
+		{name: "defaults third attempt", attempt: 3, want: 2250 * time.Millisecond},
+		{name: "defaults capped", attempt: 20, want: 30 * time.Second},
+		{name: "attempt zero", attempt: 0, want: time.Second},
+		{name: "custom", poll: ExponentialPoll{Initial: 100 * time.Millisecond, Max: time.Second, Multiplier: 2}, attempt: 4, want: 800 * time.Millisecond},
+		{name: "custom capped", poll: ExponentialPoll{Initial: 100 * time.Millisecond, Max: time.Second, Multiplier: 2}, attempt: 5, want: time.Second},
+		{name: "multiplier below one uses default", poll: ExponentialPoll{Initial: time.Second, Multiplier: 0.5}, attempt: 2, want: 1500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.poll.NextInterval(PollState{Attempt: tt.attempt}); got != tt.want {
+				t.Errorf("NextInterval(attempt %d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgressPoll(t *testing.T) {
+	tests := []struct {
+		name    string
+		poll    ProgressPoll
+		article *Article
+		elapsed time.Duration
+		want    time.Duration
+	}{
+		{name: "no article", want: 2 * time.Second},
+		{name: "no progress", article: &Article{}, elapsed: time.Minute, want: 2 * time.Second},
+		{name: "half of remaining", article: &Article{Progress: 50}, elapsed: 20 * time.Second, want: 10 * time.Second},
+		{name: "clamped to max", article: &Article{Progress: 10}, elapsed: time.Minute, want: 30 * time.Second},
+		{name: "clamped to min", article: &Article{Progress: 95}, elapsed: 10 * time.Second, want: 2 * time.Second},
+		{name: "custom fraction and bounds", poll: ProgressPoll{Min: time.Second, Max: time.Minute, Fraction: 0.25}, article: &Article{Progress: 20}, elapsed: 10 * time.Second, want: 10 * time.Second},
+		{name: "invalid fraction uses default", poll: ProgressPoll{Fraction: 2}, article: &Article{Progress: 50}, elapsed: 20 * time.Second, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.poll.NextInterval(PollState{Attempt: 1, Elapsed: tt.elapsed, Article: tt.article})
+			if got != tt.want {
+				t.Errorf("NextInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateRemaining(t *testing.T) {
+	tests := []struct {
+		name    string
+		article *Article
+		elapsed time.Duration
+		want    time.Duration
+		wantOK  bool
+	}{
+		{name: "nil article"},
+		{name: "not started", article: &Article{}, elapsed: time.Second},
+		{name: "complete", article: &Article{Progress: 100}, elapsed: time.Second},
+		{name: "no elapsed time", article: &Article{Progress: 50}},
+		{name: "from elapsed", article: &Article{Progress: 25}, elapsed: 10 * time.Second, want: 30 * time.Second, wantOK: true},
+		{name: "from creation time", article: &Article{Progress: 50, CreatedAt: time.Now().Add(-20 * time.Second)}, elapsed: 5 * time.Second, want: 20 * time.Second, wantOK: true},
+		{name: "creation time after start", article: &Article{Progress: 50, CreatedAt: time.Now().Add(time.Hour)}, elapsed: 10 * time.Second, want: 10 * time.Second, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := estimateRemaining(tt.article, tt.elapsed)
+			// Allow for the time elapsed since the test table was built
+			if ok != tt.wantOK || got < tt.want || got > tt.want+time.Second {
+				t.Errorf("estimateRemaining() = %v, %v; want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestWaitForArticleTimeoutClampsDelay(t *testing.T) {
+	server, requests := articleServer(t, "processing")
+	client := NewClient("key", &Config{BaseURL: server.URL})
+
+	start := time.Now()
+	_, err := client.WaitForArticleWithContext(context.Background(), "a1", &GenerateAndWaitOptions{
+		Timeout:      50 * time.Millisecond,
+		PollStrategy: FixedPoll{Interval: time.Hour},
+	})
+
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) || timeout.Timeout != 50*time.Millisecond {
+		t.Fatalf("WaitForArticleWithContext() error = %v, want a TimeoutError", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("wait took %v, want the hour-long poll interval clamped to the timeout", elapsed)
+	}
+	if *requests < 2 || timeout.Attempts != int(*requests) {
+		t.Errorf("%d requests, %d attempts reported; want a final check at the deadline", *requests, timeout.Attempts)
+	}
+}
@@ -0,0 +1,46 @@
+package semanticpen
+
+import "time"
+
+// ProgressEventKind distinguishes status transitions from unchanged polls
+type ProgressEventKind string
+
+const (
+	// ProgressStatusChanged is emitted when the status differs from the previous
+	// check, including the first check of a wait
+	ProgressStatusChanged ProgressEventKind = "status_changed"
+	// ProgressPolled is emitted when a check finds the status unchanged
+	ProgressPolled ProgressEventKind = "polled"
+)
+
+// ProgressEvent describes one status check made while waiting for an article
+type ProgressEvent struct {
+	Kind               ProgressEventKind
+	Attempt            int
+	Status             ArticleStatus
+	PreviousStatus     ArticleStatus // Empty on the first check
+	Progress           int           // Percent complete, 0-100
+	Elapsed            time.Duration // Time since waiting started
+	EstimatedRemaining time.Duration // Extrapolated from Progress; zero when unknown
+	Article            *Article      // Full snapshot returned by the API
+}
+
+// newProgressEvent builds the event for a status check
+func newProgressEvent(attempt int, previous ArticleStatus, elapsed time.Duration, article *Article) ProgressEvent {
+	event := ProgressEvent{
+		Kind:           ProgressPolled,
+		Attempt:        attempt,
+		Status:         article.Status,
+		PreviousStatus: previous,
+		Progress:       article.Progress,
+		Elapsed:        elapsed,
+		Article:        article,
+	}
+	if article.Status != previous {
+		event.Kind = ProgressStatusChanged
+	}
+	if remaining, ok := estimateRemaining(article, elapsed); ok {
+		event.EstimatedRemaining = remaining
+	}
+	return event
+}
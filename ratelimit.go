@@ -0,0 +1,132 @@
+package semanticpen
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRateLimitMaxRetries is the number of transparent waits performed
+	// when RateLimitWait.MaxRetries is not set
+	DefaultRateLimitMaxRetries = 3
+
+	// DefaultRateLimitMaxWait is the longest Retry-After slept through when
+	// RateLimitWait.MaxWait is not set
+	DefaultRateLimitMaxWait = 30 * time.Second
+)
+
+// RateLimitWait enables transparently sleeping and retrying when the API
+// responds with 429 Too Many Requests and a Retry-After below MaxWait.
+// Because a 429 means the request was rejected, this applies to every method,
+// including article generation.
+type RateLimitWait struct {
+	MaxWait    time.Duration // Longest Retry-After the client will sleep through; longer waits return a *RateLimitError. Defaults to DefaultRateLimitMaxWait
+	MaxRetries int           // Transparent waits per request; defaults to DefaultRateLimitMaxRetries
+}
+
+// withDefaults returns a copy of the settings with zero values filled in
+func (w *RateLimitWait) withDefaults() *RateLimitWait {
+	if w == nil {
+		return nil
+	}
+
+	wait := *w
+	if wait.MaxRetries <= 0 {
+		wait.MaxRetries = DefaultRateLimitMaxRetries
+	}
+	if wait.MaxWait <= 0 {
+		wait.MaxWait = DefaultRateLimitMaxWait
+	}
+	return &wait
+}
+
+// rateLimitBody mirrors the fields the API may return in a 429 response body
+type rateLimitBody struct {
+	Message    string      `json:"message"`
+	Error      string      `json:"error"`
+	RetryAfter json.Number `json:"retryAfter"`
+}
+
+// newRateLimitError builds a RateLimitError from a 429 response and its body
+func newRateLimitError(header http.Header, body []byte) *RateLimitError {
+	rlErr := &RateLimitError{Message: http.StatusText(http.StatusTooManyRequests)}
+
+	var parsed rateLimitBody
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		if parsed.Message != "" {
+			rlErr.Message = parsed.Message
+		} else if parsed.Error != "" {
+			rlErr.Message = parsed.Error
+		}
+	} else if text := strings.TrimSpace(string(body)); text != "" {
+		rlErr.Message = text
+	}
+
+	if wait, ok := parseRetryAfter(header.Get("Retry-After"), time.Now()); ok {
+		rlErr.RetryAfter = ceilSeconds(wait)
+	} else if seconds, err := parsed.RetryAfter.Float64(); err == nil && seconds > 0 {
+		rlErr.RetryAfter = int(math.Ceil(seconds))
+	}
+
+	return rlErr
+}
+
+// parseRetryAfter parses a Retry-After header given either as delay seconds or
+// as an HTTP-date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := date.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+// ceilSeconds rounds a duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// rateLimitDelay inspects a 429 response and reports how long to wait before
+// retrying. The response body is consumed and replaced so that callers can
+// still read it when no wait is performed.
+func (w *RateLimitWait) rateLimitDelay(resp *http.Response) (time.Duration, bool) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 0, false
+	}
+
+	rlErr := newRateLimitError(resp.Header, body)
+	if rlErr.RetryAfter <= 0 {
+		return 0, false
+	}
+
+	delay := time.Duration(rlErr.RetryAfter) * time.Second
+	if delay > w.MaxWait {
+		return 0, false
+	}
+	return delay, true
+}
@@ -0,0 +1,144 @@
+package semanticpen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "5", want: 5 * time.Second, wantOK: true},
+		{value: " 0 ", want: 0, wantOK: true},
+		{value: "Fri, 01 Mar 2024 12:00:30 GMT", want: 30 * time.Second, wantOK: true},
+		{value: "Fri, 01 Mar 2024 11:59:00 GMT", want: 0, wantOK: true},
+		{value: "Friday, 01-Mar-24 12:01:00 GMT", want: time.Minute, wantOK: true},
+		{value: ""},
+		{value: "-3"},
+		{value: "1.5"},
+		{value: "soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNewRateLimitError(t *testing.T) {
+	tests := []struct {
+		name        string
+		retryAfter  string
+		body        string
+		wantMessage string
+		wantAfter   int
+	}{
+		{name: "header seconds", retryAfter: "7", body: `{"message": "slow down"}`, wantMessage: "slow down", wantAfter: 7},
+		{name: "header date", retryAfter: time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat), wantMessage: "Too Many Requests", wantAfter: 90},
+		{name: "body number", body: `{"error": "quota", "retryAfter": 12.2}`, wantMessage: "quota", wantAfter: 13},
+		{name: "body string", body: `{"retryAfter": "4"}`, wantMessage: "Too Many Requests", wantAfter: 4},
+		{name: "header beats body", retryAfter: "2", body: `{"retryAfter": 60}`, wantMessage: "Too Many Requests", wantAfter: 2},
+		{name: "plain text body", body: "try later\n", wantMessage: "try later"},
+		{name: "invalid header falls back to body", retryAfter: "soon", body: `{"retryAfter": 3}`, wantMessage: "Too Many Requests", wantAfter: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.retryAfter != "" {
+				header.Set("Retry-After", tt.retryAfter)
+			}
+			err := newRateLimitError(header, []byte(tt.body))
+			// An HTTP-date has one-second resolution, so allow for the clock ticking
+			if err.Message != tt.wantMessage || err.RetryAfter < tt.wantAfter-1 || err.RetryAfter > tt.wantAfter {
+				t.Errorf("newRateLimitError() = %+v, want message %q and retry after %d", err, tt.wantMessage, tt.wantAfter)
+			}
+		})
+	}
+}
+
+// rateLimitServer answers the first limited requests with 429 and the given
+// Retry-After, then with an article
+func rateLimitServer(t *testing.T, limited int32, retryAfter string) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= limited {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"message": "slow down"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id": "a1", "status": "finished"}`)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestRateLimitWait(t *testing.T) {
+	tests := []struct {
+		name         string
+		wait         *RateLimitWait
+		limited      int32
+		retryAfter   string
+		wantErr      bool
+		wantRequests int32
+	}{
+		{name: "disabled", limited: 1, retryAfter: "1", wantErr: true, wantRequests: 1},
+		{name: "waits under max wait", wait: &RateLimitWait{MaxWait: 2 * time.Second}, limited: 1, retryAfter: "1", wantRequests: 2},
+		{name: "default max wait", wait: &RateLimitWait{}, limited: 1, retryAfter: "1", wantRequests: 2},
+		{name: "gives up past max wait", wait: &RateLimitWait{MaxWait: 2 * time.Second}, limited: 1, retryAfter: "60", wantErr: true, wantRequests: 1},
+		{name: "gives up past default max wait", wait: &RateLimitWait{}, limited: 1, retryAfter: "31", wantErr: true, wantRequests: 1},
+		{name: "gives up after max retries", wait: &RateLimitWait{MaxWait: time.Minute, MaxRetries: 1}, limited: 2, retryAfter: "1", wantErr: true, wantRequests: 2},
+		{name: "no retry after", wait: &RateLimitWait{MaxWait: time.Minute}, limited: 1, retryAfter: "", wantErr: true, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server, requests := rateLimitServer(t, tt.limited, tt.retryAfter)
+			client := NewClient("key", &Config{BaseURL: server.URL, RateLimitWait: tt.wait})
+
+			article, err := client.GetArticleWithContext(context.Background(), "a1")
+			var rlErr *RateLimitError
+			switch {
+			case tt.wantErr && (!errors.As(err, &rlErr) || rlErr.Message != "slow down"):
+				t.Errorf("GetArticle() error = %v, want a *RateLimitError", err)
+			case !tt.wantErr && (err != nil || article.ID != "a1"):
+				t.Errorf("GetArticle() = %+v, %v; want the article after waiting", article, err)
+			}
+			if *requests != tt.wantRequests {
+				t.Errorf("%d requests, want %d", *requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestRateLimitWaitHonorsContext(t *testing.T) {
+	server, requests := rateLimitServer(t, 1, "30")
+	client := NewClient("key", &Config{BaseURL: server.URL, RateLimitWait: &RateLimitWait{MaxWait: time.Minute}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetArticleWithContext(ctx, "a1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetArticle() error = %v, want context.DeadlineExceeded", err)
+	}
+	if *requests != 1 {
+		t.Errorf("%d requests, want 1", *requests)
+	}
+}
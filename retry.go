@@ -0,0 +1,126 @@
+package semanticpen
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+	DefaultRetryMaxDelay  = 30 * time.Second
+)
+
+// DefaultRetryableStatusCodes are the HTTP status codes retried when a
+// RetryPolicy does not list its own
+var DefaultRetryableStatusCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy configures automatic retries with exponential backoff.
+// Retries are applied to idempotent requests (GET, HEAD, OPTIONS, PUT, DELETE).
+// Article generation POSTs are only retried when RetryIdempotentPosts is set
+// and the request carries an idempotency key.
+type RetryPolicy struct {
+	MaxRetries           int                    // Number of retries after the first attempt
+	BaseDelay            time.Duration          // Delay before the first retry, doubled on each subsequent retry
+	MaxDelay             time.Duration          // Upper bound for a single backoff delay
+	Jitter               float64                // Fraction (0-1) of each delay that is randomized
+	RetryableStatusCodes []int                  // Status codes that trigger a retry; defaults to DefaultRetryableStatusCodes
+	RetryOnNetworkError  bool                   // Retry when the request fails without a response (reset, DNS, timeout)
+	RetryIdempotentPosts bool                   // Also retry POSTs with an Idempotency-Key header; the server must honor the key
+	OnRetry              func(event RetryEvent) // Called before sleeping ahead of each retry
+}
+
+// RetryEvent describes a retry that is about to happen
+type RetryEvent struct {
+	Method     string
+	Endpoint   string
+	Attempt    int           // Retry number, starting at 1
+	StatusCode int           // Status of the failed attempt, 0 for network errors
+	Err        error         // Network error of the failed attempt, if any
+	Delay      time.Duration // Backoff before the next attempt
+}
+
+// DefaultRetryPolicy returns a retry policy suitable for most workloads
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:          3,
+		BaseDelay:           DefaultRetryBaseDelay,
+		MaxDelay:            DefaultRetryMaxDelay,
+		Jitter:              0.2,
+		RetryOnNetworkError: true,
+	}
+}
+
+// withDefaults returns a copy of the policy with zero values filled in
+func (p *RetryPolicy) withDefaults() *RetryPolicy {
+	if p == nil {
+		return nil
+	}
+
+	policy := *p
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = DefaultRetryBaseDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = DefaultRetryMaxDelay
+	}
+	if policy.Jitter < 0 {
+		policy.Jitter = 0
+	}
+	if policy.Jitter > 1 {
+		policy.Jitter = 1
+	}
+	if len(policy.RetryableStatusCodes) == 0 {
+		policy.RetryableStatusCodes = DefaultRetryableStatusCodes
+	}
+	return &policy
+}
+
+// shouldRetry reports whether a failed attempt is worth retrying
+func (p *RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return p.RetryOnNetworkError
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// retriesKeyed reports whether a request with the given headers may be
+// retried because it carries an idempotency key the policy trusts
+func (p *RetryPolicy) retriesKeyed(header http.Header) bool {
+	return p.RetryIdempotentPosts && header.Get(IdempotencyKeyHeader) != ""
+}
+
+// backoff returns the delay before the given retry (starting at 1)
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * p.Jitter * float64(delay))
+	}
+	return delay
+}
+
+// isIdempotent reports whether an HTTP method can safely be repeated
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+package semanticpen
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pushkarsingh32/semanticpen-go-sdk/internal/htmlnode"
+)
+
+// SanitizePolicy is an allowlist of HTML elements and attributes plus a set of
+// normalization passes applied to article HTML. Elements outside the allowlist
+// are unwrapped (their text is kept), except for script-like elements, whose
+// content is dropped entirely. Event handler attributes (on*) and URLs with
+// schemes outside AllowedSchemes are always removed.
+type SanitizePolicy struct {
+	AllowedElements    []string            // Elements kept as-is
+	AllowedAttributes  map[string][]string // Attributes kept per element; the "*" entry applies to every element
+	AllowedSchemes     []string            // URL schemes allowed in href and src; relative URLs are always allowed
+	AllowedIframeHosts []string            // Hosts iframes may load from; iframes must also be in AllowedElements
+
+	StripInlineStyles bool   // Remove style attributes
+	AddNoopener       bool   // Add rel="noopener" to links pointing outside BaseURL's host
+	LazyLoadImages    bool   // Add loading="lazy" to images that do not set loading
+	BaseURL           string // Resolve relative href and src values against this URL
+}
+
+// droppedElements lose their content as well as their tags when not allowed
+var droppedElements = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"applet": true, "noscript": true, "template": true, "head": true, "title": true,
+	"form": true, "input": true, "button": true, "select": true, "textarea": true,
+	"link": true, "meta": true, "base": true, "frame": true, "frameset": true, "svg": true, "math": true,
+}
+
+// urlAttributes hold URLs that are checked against AllowedSchemes
+var urlAttributes = map[string]bool{
+	"href": true, "src": true, "cite": true, "action": true, "formaction": true,
+	"poster": true, "background": true, "longdesc": true,
+}
+
+// DefaultSanitizePolicy returns a policy suited to embedding article HTML in a
+// CMS: common text, list, table, image and figure markup, http(s) and mailto
+// links, no iframes, and every normalization pass enabled
+func DefaultSanitizePolicy() *SanitizePolicy {
+	return &SanitizePolicy{
+		AllowedElements: []string{
+			"a", "abbr", "article", "aside", "b", "blockquote", "br", "caption", "cite",
+			"code", "dd", "del", "div", "dl", "dt", "em", "figcaption", "figure", "footer",
+			"h1", "h2", "h3", "h4", "h5", "h6", "header", "hr", "i", "img", "ins", "kbd",
+			"li", "main", "mark", "nav", "ol", "p", "pre", "q", "s", "section", "small",
+			"span", "strong", "sub", "sup", "table", "tbody", "td", "tfoot", "th", "thead",
+			"time", "tr", "u", "ul",
+		},
+		AllowedAttributes: map[string][]string{
+			"*":          {"class", "id", "title", "lang", "dir"},
+			"a":          {"href", "rel", "target", "name"},
+			"img":        {"src", "alt", "width", "height", "loading", "srcset", "sizes"},
+			"td":         {"colspan", "rowspan", "align"},
+			"th":         {"colspan", "rowspan", "align", "scope"},
+			"ol":         {"start", "reversed", "type"},
+			"blockquote": {"cite"},
+			"q":          {"cite"},
+			"time":       {"datetime"},
+		},
+		AllowedSchemes:    []string{"http", "https", "mailto"},
+		StripInlineStyles: true,
+		AddNoopener:       true,
+		LazyLoadImages:    true,
+	}
+}
+
+// SanitizeHTML cleans an HTML fragment according to policy, or
+// DefaultSanitizePolicy when policy is nil
+func SanitizeHTML(source string, policy *SanitizePolicy) string {
+	if policy == nil {
+		policy = DefaultSanitizePolicy()
+	}
+
+	s := newSanitizer(policy)
+	doc := htmlnode.Parse(source)
+	s.clean(doc)
+	return doc.String()
+}
+
+// SanitizeHTML replaces the article's HTML with its sanitized form
+func (a *Article) SanitizeHTML(policy *SanitizePolicy) {
+	if a.ArticleHTML != "" {
+		a.ArticleHTML = SanitizeHTML(a.ArticleHTML, policy)
+	}
+}
+
+// sanitizer is a SanitizePolicy compiled into lookup tables
+type sanitizer struct {
+	policy      *SanitizePolicy
+	elements    map[string]bool
+	attributes  map[string]map[string]bool
+	schemes     map[string]bool
+	iframeHosts map[string]bool
+	base        *url.URL
+}
+
+func newSanitizer(policy *SanitizePolicy) *sanitizer {
+	s := &sanitizer{
+		policy:      policy,
+		elements:    toSet(policy.AllowedElements),
+		attributes:  make(map[string]map[string]bool),
+		schemes:     toSet(policy.AllowedSchemes),
+		iframeHosts: toSet(policy.AllowedIframeHosts),
+	}
+	for element, attrs := range policy.AllowedAttributes {
+		s.attributes[strings.ToLower(element)] = toSet(attrs)
+	}
+	if policy.BaseURL != "" {
+		if base, err := url.Parse(policy.BaseURL); err == nil && base.IsAbs() {
+			s.base = base
+		}
+	}
+	return s
+}
+
+// clean sanitizes the children of n in place
+func (s *sanitizer) clean(n *htmlnode.Node) {
+	var children []*htmlnode.Node
+	for _, c := range n.Children {
+		switch c.Type {
+		case htmlnode.TextNode:
+			children = append(children, c)
+		case htmlnode.ElementNode:
+			s.clean(c)
+			switch {
+			case s.allowElement(c):
+				s.cleanAttributes(c)
+				children = append(children, c)
+			case droppedElements[c.Data]:
+			default:
+				for _, grandchild := range c.Children {
+					grandchild.Parent = n
+					children = append(children, grandchild)
+				}
+			}
+		}
+	}
+	n.Children = children
+}
+
+// allowElement reports whether an element is kept
+func (s *sanitizer) allowElement(n *htmlnode.Node) bool {
+	if !s.elements[n.Data] {
+		return false
+	}
+	if n.Data == "iframe" {
+		src, err := url.Parse(n.Get("src"))
+		return err == nil && s.iframeHosts[strings.ToLower(src.Hostname())]
+	}
+	return true
+}
+
+// cleanAttributes filters attributes and applies the normalization passes
+func (s *sanitizer) cleanAttributes(n *htmlnode.Node) {
+	var attrs []htmlnode.Attr
+	for _, a := range n.Attr {
+		key := a.Key
+		if strings.HasPrefix(key, "on") {
+			continue
+		}
+		if key == "style" && s.policy.StripInlineStyles {
+			continue
+		}
+		if !s.attributes["*"][key] && !s.attributes[n.Data][key] {
+			continue
+		}
+		if urlAttributes[key] {
+			val, ok := s.cleanURL(a.Val)
+			if !ok {
+				continue
+			}
+			a.Val = val
+		}
+		attrs = append(attrs, a)
+	}
+	n.Attr = attrs
+
+	switch n.Data {
+	case "a":
+		if s.policy.AddNoopener && s.isExternal(n.Get("href")) {
+			addRel(n, "noopener")
+		}
+	case "img":
+		if s.policy.LazyLoadImages && n.Get("loading") == "" {
+			n.SetAttr("loading", "lazy")
+		}
+	}
+}
+
+// cleanURL validates a URL's scheme and resolves it against BaseURL
+func (s *sanitizer) cleanURL(raw string) (string, bool) {
+	trimmed := strings.TrimSpace(raw)
+	u, err := url.Parse(trimmed)
+	if err != nil {
+		return "", false
+	}
+	if u.Scheme != "" && !s.schemes[strings.ToLower(u.Scheme)] {
+		return "", false
+	}
+	if u.Scheme == "" && s.base != nil && !strings.HasPrefix(trimmed, "#") {
+		return s.base.ResolveReference(u).String(), true
+	}
+	return trimmed, true
+}
+
+// isExternal reports whether href points to another site than BaseURL
+func (s *sanitizer) isExternal(href string) bool {
+	u, err := url.Parse(href)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "" {
+		return false
+	}
+	return s.base == nil || !strings.EqualFold(u.Hostname(), s.base.Hostname())
+}
+
+// addRel adds a token to an element's rel attribute
+func addRel(n *htmlnode.Node, token string) {
+	rel := strings.Fields(n.Get("rel"))
+	for _, existing := range rel {
+		if strings.EqualFold(existing, token) {
+			return
+		}
+	}
+	n.SetAttr("rel", strings.Join(append(rel, token), " "))
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[strings.ToLower(v)] = true
+	}
+	return set
+}
@@ -0,0 +1,78 @@
+package semanticpen
+
+import "testing"
+
+func TestSanitizeHTML(t *testing.T) {
+	withBase := DefaultSanitizePolicy()
+	withBase.BaseURL = "https://example.com/blog/"
+
+	withIframes := DefaultSanitizePolicy()
+	withIframes.AllowedElements = append(withIframes.AllowedElements, "iframe")
+	withIframes.AllowedAttributes["iframe"] = []string{"src"}
+	withIframes.AllowedIframeHosts = []string{"www.youtube.com"}
+
+	tests := []struct {
+		name   string
+		policy *SanitizePolicy
+		input  string
+		want   string
+	}{
+		// URL schemes
+		{name: "javascript href", input: `<a href="javascript:alert(1)">x</a>`, want: `<a>x</a>`},
+		{name: "mixed case and padded scheme", input: `<a href="  JaVaScRiPt:alert(1)">x</a>`, want: `<a>x</a>`},
+		{name: "entity encoded scheme", input: `<a href="&#106;avascript:alert(1)">x</a>`, want: `<a>x</a>`},
+		{name: "hex entity encoded scheme", input: `<a href="&#x6A;&#x61;vascript:alert(1)">x</a>`, want: `<a>x</a>`},
+		{name: "entity encoded colon", input: `<a href="javascript&colon;alert(1)">x</a>`, want: `<a>x</a>`},
+		{name: "tab inside scheme", input: "<a href=\"jav\tascript:alert(1)\">x</a>", want: `<a>x</a>`},
+		{name: "entity encoded tab inside scheme", input: `<a href="jav&#x09;ascript:alert(1)">x</a>`, want: `<a>x</a>`},
+		{name: "newline inside scheme", input: "<a href=\"java\nscript:alert(1)\">x</a>", want: `<a>x</a>`},
+		{name: "leading control character", input: `<a href="&#x01;javascript:alert(1)">x</a>`, want: `<a>x</a>`},
+		{name: "data url", input: `<a href="data:text/html;base64,PHNjcmlwdD4=">x</a>`, want: `<a>x</a>`},
+		{name: "vbscript src", input: `<img src="vbscript:msgbox(1)" alt="a">`, want: `<img alt="a" loading="lazy">`},
+		{name: "javascript cite", input: `<blockquote cite="javascript:alert(1)">q</blockquote>`, want: `<blockquote>q</blockquote>`},
+		{name: "allowed schemes", input: `<a href="mailto:a@example.com">m</a><a href="#top">t</a>`, want: `<a href="mailto:a@example.com">m</a><a href="#top">t</a>`},
+
+		// Event handlers
+		{name: "unquoted onerror", input: `<img src=x.png onerror=alert(1)>`, want: `<img src="x.png" loading="lazy">`},
+		{name: "uppercase handler", input: `<img src="x.png" ONERROR="alert(1)">`, want: `<img src="x.png" loading="lazy">`},
+		{name: "several handlers", input: `<p onclick="a()" onmouseover='b()' class="c">t</p>`, want: `<p class="c">t</p>`},
+		{name: "inline style", input: `<p style="background:url(javascript:alert(1))">t</p>`, want: `<p>t</p>`},
+
+		// Dropped elements
+		{name: "script", input: `<p>a</p><script>alert(1)</script><p>b</p>`, want: `<p>a</p><p>b</p>`},
+		{name: "uppercase script", input: `<SCRIPT>alert(1)</SCRIPT>ok`, want: `ok`},
+		{name: "style", input: `<style>p { color: red }</style>ok`, want: `ok`},
+		{name: "iframe not allowed", input: `<iframe src="https://www.youtube.com/embed/x"></iframe>ok`, want: `ok`},
+		{name: "iframe from other host", policy: withIframes, input: `<iframe src="https://evil.example/x"></iframe>ok`, want: `ok`},
+		{name: "iframe from allowed host", policy: withIframes, input: `<iframe src="https://www.youtube.com/embed/x"></iframe>`, want: `<iframe src="https://www.youtube.com/embed/x"></iframe>`},
+		{name: "script inside svg", input: `<svg><script>alert(1)</script></svg>ok`, want: `ok`},
+		{name: "object and embed", input: `<object data="x.swf"><embed src="x.swf"></object>ok`, want: `ok`},
+		{name: "unknown element unwrapped", input: `<custom-tag onclick="x()">text</custom-tag>`, want: `text`},
+
+		// Quoting
+		{name: "attribute quote breakout", input: `<p title='x" onmouseover="alert(1)'>t</p>`, want: `<p title="x&#34; onmouseover=&#34;alert(1)">t</p>`},
+		{name: "markup in attribute", input: `<p title="&quot;&gt;&lt;script&gt;alert(1)&lt;/script&gt;">t</p>`, want: `<p title="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;">t</p>`},
+		{name: "escaped markup in text", input: `<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>`, want: `<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>`},
+
+		// Normalization
+		{name: "noopener without base", input: `<a href="https://other.example/x">x</a><a href="/local">y</a>`, want: `<a href="https://other.example/x" rel="noopener">x</a><a href="/local">y</a>`},
+		{name: "noopener keeps rel", input: `<a href="https://other.example/x" rel="nofollow">x</a>`, want: `<a href="https://other.example/x" rel="nofollow noopener">x</a>`},
+		{name: "noopener not duplicated", input: `<a href="https://other.example/x" rel="NOOPENER">x</a>`, want: `<a href="https://other.example/x" rel="NOOPENER">x</a>`},
+		{name: "same host link", policy: withBase, input: `<a href="https://EXAMPLE.com/z">z</a>`, want: `<a href="https://EXAMPLE.com/z">z</a>`},
+		{name: "lazy loading", input: `<img src="a.png"><img src="b.png" loading="eager">`, want: `<img src="a.png" loading="lazy"><img src="b.png" loading="eager">`},
+		{
+			name:   "base url rewriting",
+			policy: withBase,
+			input:  `<a href="/local">a</a><a href="post">b</a><a href="#top">c</a><img src="../img/x.png">`,
+			want:   `<a href="https://example.com/local">a</a><a href="https://example.com/blog/post">b</a><a href="#top">c</a><img src="https://example.com/img/x.png" loading="lazy">`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeHTML(tt.input, tt.policy); got != tt.want {
+				t.Errorf("SanitizeHTML(%q)\n got %s\nwant %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pushkarsingh32/semanticpen-go-sdk"
+	"github.com/pushkarsingh32/semanticpen-go-sdk/internal/htmlnode"
+)
+
+// Options configures the nodes generated by FromArticle
+type Options struct {
+	Type      string        // TypeArticle, TypeBlogPosting or TypeNewsArticle; defaults to TypeBlogPosting
+	URL       string        // Canonical page URL, used for mainEntityOfPage
+	Author    *Person       // Required for a valid Article; FromArticle leaves author empty when nil
+	Publisher *Organization // Optional publisher
+	Image     string        // Defaults to the first image in the article
+	Language  string        // BCP 47 language code, e.g. en-US
+}
+
+// FromArticle builds an Article node from the article's title, SEO data and
+// timestamps, followed by an FAQPage node when the article has FAQs
+func FromArticle(article *semanticpen.Article, options *Options) []Node {
+	if options == nil {
+		options = &Options{}
+	}
+	typ := options.Type
+	if typ == "" {
+		typ = TypeBlogPosting
+	}
+
+	content, _ := article.Content()
+	node := &Article{
+		Type:             typ,
+		Headline:         truncate(articleHeadline(article, content), MaxHeadlineLength),
+		MainEntityOfPage: Reference(options.URL),
+		Publisher:        options.Publisher,
+		InLanguage:       options.Language,
+	}
+	if seo := article.SEOData; seo != nil {
+		node.Description = seo.Description
+		node.Keywords = TextList(strings.Join(seo.Keywords, ", "))
+	}
+	if options.Author != nil {
+		author := *options.Author
+		if author.Type == "" {
+			author.Type = "Person"
+		}
+		node.Author = PersonList{author}
+	}
+	if image := options.Image; image != "" {
+		node.Image = ImageList{image}
+	} else if image := firstImage(article, content); image != "" {
+		node.Image = ImageList{image}
+	}
+	if !article.CreatedAt.IsZero() {
+		node.DatePublished = article.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	if !article.UpdatedAt.IsZero() {
+		node.DateModified = article.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	if analysis, err := semanticpen.Analyze(article, nil); err == nil {
+		node.WordCount = Integer(analysis.WordCount)
+	}
+
+	nodes := []Node{node}
+	if content != nil && len(content.FAQs) > 0 {
+		faq := &FAQPage{Type: TypeFAQPage}
+		for _, item := range content.FAQs {
+			faq.MainEntity = append(faq.MainEntity, Question{
+				Type:           "Question",
+				Name:           item.Question,
+				AcceptedAnswer: Answer{Type: "Answer", Text: item.Answer},
+			})
+		}
+		nodes = append(nodes, faq)
+	}
+	return nodes
+}
+
+// Script renders nodes as a JSON-LD <script> block. Several nodes are
+// combined into an @graph under a single @context.
+func Script(nodes ...Node) (string, error) {
+	if len(nodes) == 0 {
+		return "", fmt.Errorf("no schema nodes to render")
+	}
+
+	var graph []map[string]interface{}
+	for _, node := range nodes {
+		fields, err := toMap(node)
+		if err != nil {
+			return "", err
+		}
+		delete(fields, "@context")
+		graph = append(graph, fields)
+	}
+
+	doc := map[string]interface{}{"@context": Context}
+	if len(graph) == 1 {
+		for k, v := range graph[0] {
+			doc[k] = v
+		}
+	} else {
+		doc["@graph"] = graph
+	}
+	return scriptTag(doc)
+}
+
+// ArticleScript returns a JSON-LD <script> block for the article. The schema
+// returned by the API is used when it validates; otherwise one is generated
+// with FromArticle. A generated schema that does not validate, for example
+// because Options.Author is nil, is returned as a Problems error.
+func ArticleScript(article *semanticpen.Article, options *Options) (string, error) {
+	if article.SEOData != nil && len(article.SEOData.Schema) > 0 && len(Validate(article.SEOData.Schema)) == 0 {
+		return scriptTag(article.SEOData.Schema)
+	}
+
+	nodes := FromArticle(article, options)
+	var problems Problems
+	for _, node := range nodes {
+		problems = append(problems, node.Validate()...)
+	}
+	if len(problems) > 0 {
+		return "", problems
+	}
+	return Script(nodes...)
+}
+
+// scriptTag marshals doc into a script element. encoding/json escapes <, >
+// and &, so the content cannot close the element early.
+func scriptTag(doc interface{}) (string, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode schema: %w", err)
+	}
+	return `<script type="application/ld+json">` + string(data) + `</script>`, nil
+}
+
+func toMap(node Node) (map[string]interface{}, error) {
+	data, err := json.Marshal(node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", node.SchemaType(), err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", node.SchemaType(), err)
+	}
+	return fields, nil
+}
+
+func articleHeadline(article *semanticpen.Article, content *semanticpen.ArticleContent) string {
+	if article.SEOData != nil && article.SEOData.Title != "" {
+		return article.SEOData.Title
+	}
+	if article.Title != "" {
+		return article.Title
+	}
+	if content != nil && content.Title != "" {
+		return content.Title
+	}
+	for _, h1 := range htmlnode.FindAll(htmlnode.Parse(article.ArticleHTML), "h1") {
+		if text := strings.Join(strings.Fields(h1.Text()), " "); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+func firstImage(article *semanticpen.Article, content *semanticpen.ArticleContent) string {
+	if content != nil {
+		for _, image := range content.AllImages() {
+			if image.URL != "" {
+				return image.URL
+			}
+		}
+	}
+	for _, img := range htmlnode.FindAll(htmlnode.Parse(article.ArticleHTML), "img") {
+		if src := img.Get("src"); src != "" {
+			return src
+		}
+	}
+	return ""
+}
+
+// truncate shortens text to at most limit runes, cutting at a word boundary
+func truncate(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)[:limit]
+	if i := strings.LastIndex(string(runes), " "); i > 0 {
+		return strings.TrimSpace(string(runes)[:i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,421 @@
+// Package schema models the schema.org JSON-LD that SemanticPen returns in
+// SEOData.Schema.
+//
+// Decode turns the untyped map into typed nodes, Validate checks them for the
+// properties search engines require, and ArticleScript produces a JSON-LD
+// <script> block for an article, generating one when the API returned none.
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Context is the @context value for schema.org vocabularies
+const Context = "https://schema.org"
+
+// Supported @type values. BlogPosting and NewsArticle are subtypes of Article
+// with the same properties and share the Article struct.
+const (
+	TypeArticle     = "Article"
+	TypeBlogPosting = "BlogPosting"
+	TypeNewsArticle = "NewsArticle"
+	TypeFAQPage     = "FAQPage"
+	TypeHowTo       = "HowTo"
+)
+
+// Node is a typed schema.org object
+type Node interface {
+	SchemaType() string
+	Validate() []Problem
+}
+
+// Article describes an Article, BlogPosting or NewsArticle
+type Article struct {
+	Context          string        `json:"@context,omitempty"`
+	Type             string        `json:"@type"`
+	Headline         string        `json:"headline"`
+	Description      string        `json:"description,omitempty"`
+	Image            ImageList     `json:"image,omitempty"`
+	Author           PersonList    `json:"author,omitempty"`
+	Publisher        *Organization `json:"publisher,omitempty"`
+	DatePublished    string        `json:"datePublished,omitempty"` // ISO 8601
+	DateModified     string        `json:"dateModified,omitempty"`  // ISO 8601
+	MainEntityOfPage Reference     `json:"mainEntityOfPage,omitempty"`
+	Keywords         TextList      `json:"keywords,omitempty"` // Comma-separated
+	WordCount        Integer       `json:"wordCount,omitempty"`
+	InLanguage       string        `json:"inLanguage,omitempty"`
+}
+
+// FAQPage lists questions and answers
+type FAQPage struct {
+	Context    string     `json:"@context,omitempty"`
+	Type       string     `json:"@type"`
+	MainEntity []Question `json:"mainEntity"`
+}
+
+// Question is a FAQPage entry
+type Question struct {
+	Type           string `json:"@type"`
+	Name           string `json:"name"`
+	AcceptedAnswer Answer `json:"acceptedAnswer"`
+}
+
+// Answer is the accepted answer to a Question
+type Answer struct {
+	Type string `json:"@type"`
+	Text string `json:"text"`
+}
+
+// HowTo describes step-by-step instructions
+type HowTo struct {
+	Context     string      `json:"@context,omitempty"`
+	Type        string      `json:"@type"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Image       ImageList   `json:"image,omitempty"`
+	TotalTime   string      `json:"totalTime,omitempty"` // ISO 8601 duration, e.g. PT30M
+	Step        []HowToStep `json:"step"`
+}
+
+// HowToStep is one step of a HowTo
+type HowToStep struct {
+	Type  string    `json:"@type"`
+	Name  string    `json:"name,omitempty"`
+	Text  string    `json:"text"`
+	URL   Reference `json:"url,omitempty"`
+	Image ImageList `json:"image,omitempty"`
+}
+
+// Person is an author
+type Person struct {
+	Type string    `json:"@type"`
+	Name string    `json:"name"`
+	URL  Reference `json:"url,omitempty"`
+}
+
+// Organization is a publisher
+type Organization struct {
+	Type string       `json:"@type"`
+	Name string       `json:"name"`
+	URL  Reference    `json:"url,omitempty"`
+	Logo *ImageObject `json:"logo,omitempty"`
+}
+
+// ImageObject is an image with optional dimensions
+type ImageObject struct {
+	Type   string `json:"@type"`
+	URL    string `json:"url"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// Reference is a URL. It decodes a string or a node such as {"@id": ...} or
+// {"@type": "WebPage", "url": ...}.
+type Reference string
+
+// TextList is comma-separated text. It decodes a string or an array of strings.
+type TextList string
+
+// Integer decodes a JSON number or a numeric string
+type Integer int
+
+// ImageList is a list of image URLs. It decodes a single URL, an ImageObject
+// or an array of either.
+type ImageList []string
+
+// PersonList is a list of people. It decodes a name, a Person object or an
+// array of either.
+type PersonList []Person
+
+func (a *Article) SchemaType() string { return a.Type }
+func (f *FAQPage) SchemaType() string { return f.Type }
+func (h *HowTo) SchemaType() string   { return h.Type }
+
+// UnmarshalJSON accepts a URL string or a node carrying @id or url
+func (r *Reference) UnmarshalJSON(data []byte) error {
+	var url string
+	if err := json.Unmarshal(data, &url); err == nil {
+		*r = Reference(url)
+		return nil
+	}
+	var node struct {
+		ID  string `json:"@id"`
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("must be a URL or a node with @id or url: %w", err)
+	}
+	*r = Reference(firstNonEmpty(node.ID, node.URL))
+	return nil
+}
+
+// UnmarshalJSON accepts a string or an array of strings
+func (t *TextList) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*t = TextList(text)
+		return nil
+	}
+	var items []string
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("must be text or a list of text: %w", err)
+	}
+	*t = TextList(strings.Join(items, ", "))
+	return nil
+}
+
+// UnmarshalJSON accepts a number or a numeric string
+func (i *Integer) UnmarshalJSON(data []byte) error {
+	var n float64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*i = Integer(n)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("must be a number: %w", err)
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil {
+		return fmt.Errorf("must be a number: %w", err)
+	}
+	*i = Integer(n)
+	return nil
+}
+
+// UnmarshalJSON accepts an ImageObject or a bare URL
+func (o *ImageObject) UnmarshalJSON(data []byte) error {
+	var url string
+	if err := json.Unmarshal(data, &url); err == nil {
+		*o = ImageObject{Type: "ImageObject", URL: url}
+		return nil
+	}
+	type imageObject ImageObject
+	return json.Unmarshal(data, (*imageObject)(o))
+}
+
+// UnmarshalJSON accepts an Organization, an array holding one, or a name
+func (o *Organization) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*o = Organization{Type: "Organization", Name: name}
+		return nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err == nil {
+		if len(items) == 0 {
+			return nil
+		}
+		data = items[0]
+	}
+	type organization Organization
+	return json.Unmarshal(data, (*organization)(o))
+}
+
+// UnmarshalJSON accepts an Answer or an array whose first item is accepted
+func (a *Answer) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err == nil {
+		if len(items) == 0 {
+			return nil
+		}
+		data = items[0]
+	}
+	type answer Answer
+	return json.Unmarshal(data, (*answer)(a))
+}
+
+// UnmarshalJSON accepts a HowToStep or plain step text
+func (s *HowToStep) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*s = HowToStep{Type: "HowToStep", Text: text}
+		return nil
+	}
+	type howToStep HowToStep
+	return json.Unmarshal(data, (*howToStep)(s))
+}
+
+// UnmarshalJSON accepts the shapes schema.org allows for image
+func (l *ImageList) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		items = []json.RawMessage{data}
+	}
+
+	*l = nil
+	for _, item := range items {
+		var url string
+		if err := json.Unmarshal(item, &url); err == nil {
+			*l = append(*l, url)
+			continue
+		}
+		var image ImageObject
+		if err := json.Unmarshal(item, &image); err != nil {
+			return fmt.Errorf("image must be a URL or ImageObject: %w", err)
+		}
+		*l = append(*l, image.URL)
+	}
+	return nil
+}
+
+// UnmarshalJSON accepts the shapes schema.org allows for author
+func (l *PersonList) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		items = []json.RawMessage{data}
+	}
+
+	*l = nil
+	for _, item := range items {
+		var name string
+		if err := json.Unmarshal(item, &name); err == nil {
+			*l = append(*l, Person{Type: "Person", Name: name})
+			continue
+		}
+		var person Person
+		if err := json.Unmarshal(item, &person); err != nil {
+			return fmt.Errorf("author must be a name or Person: %w", err)
+		}
+		*l = append(*l, person)
+	}
+	return nil
+}
+
+// Decode converts a JSON-LD object, including one with an @graph, into typed
+// nodes. Nodes of unsupported types are skipped; the returned types lists
+// every @type that was seen. Properties whose values cannot be decoded are
+// left unset and reported as Problems, while the rest of the node is kept.
+func Decode(raw map[string]interface{}) (nodes []Node, types []string, err error) {
+	nodes, types, problems := decode(raw)
+	if len(problems) > 0 {
+		return nodes, types, Problems(problems)
+	}
+	return nodes, types, nil
+}
+
+// Problems lists several Problem values as one error
+type Problems []Problem
+
+func (p Problems) Error() string {
+	messages := make([]string, len(p))
+	for i, problem := range p {
+		messages[i] = problem.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func decode(raw map[string]interface{}) (nodes []Node, types []string, problems []Problem) {
+	items := []interface{}{raw}
+	if graph, ok := raw["@graph"].([]interface{}); ok {
+		items = graph
+	}
+
+	for _, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		typ := nodeType(fields)
+		types = append(types, typ)
+
+		var node Node
+		switch typ {
+		case TypeArticle, TypeBlogPosting, TypeNewsArticle:
+			node = &Article{}
+		case TypeFAQPage:
+			node = &FAQPage{}
+		case TypeHowTo:
+			node = &HowTo{}
+		default:
+			continue
+		}
+
+		normalized := make(map[string]interface{}, len(fields))
+		for k, v := range fields {
+			normalized[k] = v
+		}
+		normalized["@type"] = typ
+
+		problems = append(problems, decodeNode(node, typ, normalized)...)
+		nodes = append(nodes, node)
+	}
+	return nodes, types, problems
+}
+
+// decodeNode decodes fields into node. When the node as a whole does not
+// decode, each property is decoded on its own so that one unexpected value
+// does not discard the others.
+func decodeNode(node Node, typ string, fields map[string]interface{}) []Problem {
+	if data, err := json.Marshal(fields); err == nil && json.Unmarshal(data, node) == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []Problem
+	for _, key := range keys {
+		data, err := json.Marshal(map[string]interface{}{key: fields[key]})
+		if err == nil {
+			err = json.Unmarshal(data, node)
+		}
+		if err != nil {
+			problems = append(problems, Problem{Type: typ, Property: key, Message: "has an unsupported value: " + unwrapJSONError(err)})
+		}
+	}
+	return problems
+}
+
+// unwrapJSONError trims encoding/json's Go-specific prefix from an error
+func unwrapJSONError(err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Sprintf("cannot use %s here", typeErr.Value)
+	}
+	return err.Error()
+}
+
+// nodeType returns the first supported @type of a node, or its first @type
+// when none is supported. @type may be a string or an array.
+func nodeType(fields map[string]interface{}) string {
+	var types []string
+	switch t := fields["@type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+
+	for _, t := range types {
+		switch t = strings.TrimPrefix(strings.TrimPrefix(t, "schema:"), Context+"/"); t {
+		case TypeArticle, TypeBlogPosting, TypeNewsArticle, TypeFAQPage, TypeHowTo:
+			return t
+		}
+	}
+	if len(types) > 0 {
+		return types[0]
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,184 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pushkarsingh32/semanticpen-go-sdk"
+)
+
+func parse(t *testing.T, doc string) map[string]interface{} {
+	t.Helper()
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(doc), &raw); err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+const blogPosting = `{
+	"@context": "https://schema.org",
+	"@type": ["BlogPosting"],
+	"headline": "Cold Brew at Home",
+	"mainEntityOfPage": {"@type": "WebPage", "@id": "https://example.com/cold-brew"},
+	"image": [{"@type": "ImageObject", "url": "https://example.com/a.jpg"}, "https://example.com/b.jpg"],
+	"author": [{"@type": "Person", "name": "Jane Doe"}, "John Roe"],
+	"publisher": {"@type": "Organization", "name": "Example", "logo": "https://example.com/logo.png"},
+	"keywords": ["coffee", "cold brew"],
+	"wordCount": "1200",
+	"datePublished": "2024-03-01T09:00:00Z"
+}`
+
+func TestDecodeLenientShapes(t *testing.T) {
+	nodes, types, err := Decode(parse(t, blogPosting))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(nodes) != 1 || types[0] != TypeBlogPosting {
+		t.Fatalf("Decode() = %d nodes, types %v", len(nodes), types)
+	}
+
+	article := nodes[0].(*Article)
+	if article.MainEntityOfPage != "https://example.com/cold-brew" {
+		t.Errorf("MainEntityOfPage = %q", article.MainEntityOfPage)
+	}
+	if article.Publisher == nil || article.Publisher.Logo == nil || article.Publisher.Logo.URL != "https://example.com/logo.png" {
+		t.Errorf("Publisher = %+v", article.Publisher)
+	}
+	if len(article.Image) != 2 || len(article.Author) != 2 || article.Author[1].Name != "John Roe" {
+		t.Errorf("Image = %v, Author = %v", article.Image, article.Author)
+	}
+	if article.Keywords != "coffee, cold brew" || article.WordCount != 1200 {
+		t.Errorf("Keywords = %q, WordCount = %d", article.Keywords, article.WordCount)
+	}
+	if problems := Validate(parse(t, blogPosting)); len(problems) != 0 {
+		t.Errorf("Validate() = %v, want no problems", problems)
+	}
+}
+
+func TestDecodeReportsPropertyProblems(t *testing.T) {
+	raw := parse(t, `{"@context": "https://schema.org", "@type": "Article",
+		"headline": {"text": "nested"}, "author": "Jane", "datePublished": "2024-03-01"}`)
+
+	nodes, _, err := Decode(raw)
+	if len(nodes) != 1 || nodes[0].(*Article).Author[0].Name != "Jane" {
+		t.Fatalf("Decode() dropped the decodable properties: %+v", nodes)
+	}
+	problems, ok := err.(Problems)
+	if !ok || len(problems) != 1 || problems[0].Property != "headline" {
+		t.Fatalf("Decode() error = %v, want one headline problem", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		expected []string
+		want     []string // Substrings of the expected problems, in order
+	}{
+		{
+			name: "missing article properties",
+			doc:  `{"@context": "https://schema.org", "@type": "Article"}`,
+			want: []string{"Article.headline: is required", "Article.author: is required", "Article.datePublished: is required"},
+		},
+		{
+			name: "bad date and context",
+			doc:  `{"@context": "https://example.org", "@type": "Article", "headline": "h", "author": "a", "datePublished": "March 1"}`,
+			want: []string{"must be", `"March 1" is not an ISO 8601 date`},
+		},
+		{
+			name: "faq without answer",
+			doc:  `{"@context": "https://schema.org", "@type": "FAQPage", "mainEntity": [{"@type": "Question", "name": "Why?"}]}`,
+			want: []string{"FAQPage.mainEntity[0].acceptedAnswer.text: is required"},
+		},
+		{
+			name: "howto with text steps",
+			doc:  `{"@context": "https://schema.org", "@type": "HowTo", "name": "Brew", "step": ["Grind", "Steep"]}`,
+		},
+		{
+			name:     "unexpected type",
+			doc:      `{"@context": "https://schema.org", "@type": "Article", "headline": "h", "author": "a", "datePublished": "2024-03-01"}`,
+			expected: []string{TypeFAQPage},
+			want:     []string{"expected one of FAQPage"},
+		},
+		{
+			name: "unsupported type",
+			doc:  `{"@context": "https://schema.org", "@type": "Recipe"}`,
+			want: []string{`no supported schema type, found ["Recipe"]`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := Validate(parse(t, tt.doc), tt.expected...)
+			if len(problems) != len(tt.want) {
+				t.Fatalf("Validate() = %v, want %d problems", problems, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(problems[i].Error(), want) {
+					t.Errorf("problem %d = %q, want it to contain %q", i, problems[i].Error(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestArticleScriptKeepsValidAPISchema(t *testing.T) {
+	article := &semanticpen.Article{
+		Title:   "Generated Title",
+		SEOData: &semanticpen.SEOData{Schema: parse(t, blogPosting)},
+	}
+
+	script, err := ArticleScript(article, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(script, "Cold Brew at Home") || strings.Contains(script, "Generated Title") {
+		t.Errorf("ArticleScript() replaced a valid API schema: %s", script)
+	}
+}
+
+func TestArticleScriptGenerates(t *testing.T) {
+	article := &semanticpen.Article{
+		Title:       "Cold Brew </script><script>alert(1)</script>",
+		ArticleHTML: "<p>Short body.</p>",
+		CreatedAt:   time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+		ArticleJSON: map[string]interface{}{"faqs": []interface{}{map[string]interface{}{"question": "Q?", "answer": "A."}}},
+	}
+
+	script, err := ArticleScript(article, &Options{Author: &Person{Name: "Jane"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(script, "</script>") != 1 {
+		t.Errorf("ArticleScript() did not escape the title: %s", script)
+	}
+	for _, want := range []string{`"@graph"`, `"@type":"BlogPosting"`, `"@type":"FAQPage"`, `"name":"Jane"`} {
+		if !strings.Contains(script, want) {
+			t.Errorf("ArticleScript() = %s, want it to contain %s", script, want)
+		}
+	}
+}
+
+func TestArticleScriptRejectsInvalidGeneratedSchema(t *testing.T) {
+	article := &semanticpen.Article{
+		Title:       "Cold Brew",
+		ArticleHTML: "<p>Short body.</p>",
+		CreatedAt:   time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+
+	script, err := ArticleScript(article, nil)
+	problems, ok := err.(Problems)
+	if script != "" || !ok || len(problems) != 1 || problems[0].Property != "author" {
+		t.Fatalf("ArticleScript() = %q, %v; want an author problem", script, err)
+	}
+
+	article.CreatedAt = time.Time{}
+	_, err = ArticleScript(article, &Options{Author: &Person{Name: "Jane"}})
+	if problems, ok := err.(Problems); !ok || len(problems) != 1 || problems[0].Property != "datePublished" {
+		t.Errorf("ArticleScript() error = %v, want a datePublished problem", err)
+	}
+}
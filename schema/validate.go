@@ -0,0 +1,152 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxHeadlineLength is the longest headline search engines display
+const MaxHeadlineLength = 110
+
+// Problem is a property that is missing or invalid
+type Problem struct {
+	Type     string `json:"type"`     // @type of the node, empty for document-level problems
+	Property string `json:"property"` // Path to the property, e.g. mainEntity[0].acceptedAnswer.text
+	Message  string `json:"message"`
+}
+
+func (p Problem) Error() string {
+	switch {
+	case p.Type == "":
+		return p.Message
+	case p.Property == "":
+		return fmt.Sprintf("%s: %s", p.Type, p.Message)
+	}
+	return fmt.Sprintf("%s.%s: %s", p.Type, p.Property, p.Message)
+}
+
+// Validate decodes a JSON-LD object such as SEOData.Schema and checks every
+// supported node for its required properties. When expected types are given,
+// the object must contain a node of one of them.
+func Validate(raw map[string]interface{}, expected ...string) []Problem {
+	if len(raw) == 0 {
+		return []Problem{{Message: "schema is empty"}}
+	}
+
+	var problems []Problem
+	if context, _ := raw["@context"].(string); !isSchemaContext(context) {
+		problems = append(problems, Problem{Property: "@context", Message: fmt.Sprintf("must be %q", Context)})
+	}
+
+	nodes, types, decodeProblems := decode(raw)
+	problems = append(problems, decodeProblems...)
+	if len(nodes) == 0 {
+		return append(problems, Problem{Message: fmt.Sprintf("no supported schema type, found %q", types)})
+	}
+
+	if len(expected) > 0 && !hasType(nodes, expected) {
+		problems = append(problems, Problem{Message: fmt.Sprintf("expected one of %s, found %q", strings.Join(expected, ", "), types)})
+	}
+	for _, node := range nodes {
+		problems = append(problems, node.Validate()...)
+	}
+	return problems
+}
+
+// Validate checks the properties required for article rich results
+func (a *Article) Validate() []Problem {
+	v := validator{typ: a.Type}
+	v.required("headline", a.Headline)
+	if n := utf8.RuneCountInString(a.Headline); n > MaxHeadlineLength {
+		v.add("headline", fmt.Sprintf("is %d characters, longer than %d", n, MaxHeadlineLength))
+	}
+	if len(a.Author) == 0 {
+		v.add("author", "is required")
+	}
+	for i, author := range a.Author {
+		v.required(fmt.Sprintf("author[%d].name", i), author.Name)
+	}
+	if v.required("datePublished", a.DatePublished) {
+		v.date("datePublished", a.DatePublished)
+	}
+	if a.DateModified != "" {
+		v.date("dateModified", a.DateModified)
+	}
+	if a.Publisher != nil {
+		v.required("publisher.name", a.Publisher.Name)
+	}
+	return v.problems
+}
+
+// Validate checks that every question has a name and an answer
+func (f *FAQPage) Validate() []Problem {
+	v := validator{typ: f.Type}
+	if len(f.MainEntity) == 0 {
+		v.add("mainEntity", "must list at least one question")
+	}
+	for i, question := range f.MainEntity {
+		v.required(fmt.Sprintf("mainEntity[%d].name", i), question.Name)
+		v.required(fmt.Sprintf("mainEntity[%d].acceptedAnswer.text", i), question.AcceptedAnswer.Text)
+	}
+	return v.problems
+}
+
+// Validate checks that the HowTo has a name and steps with text
+func (h *HowTo) Validate() []Problem {
+	v := validator{typ: h.Type}
+	v.required("name", h.Name)
+	if len(h.Step) == 0 {
+		v.add("step", "must list at least one step")
+	}
+	for i, step := range h.Step {
+		v.required(fmt.Sprintf("step[%d].text", i), step.Text)
+	}
+	return v.problems
+}
+
+// validator collects problems for one node
+type validator struct {
+	typ      string
+	problems []Problem
+}
+
+func (v *validator) add(property, message string) {
+	v.problems = append(v.problems, Problem{Type: v.typ, Property: property, Message: message})
+}
+
+// required records a problem when value is blank and reports whether it was set
+func (v *validator) required(property, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.add(property, "is required")
+		return false
+	}
+	return true
+}
+
+// date checks for an ISO 8601 date or date-time
+func (v *validator) date(property, value string) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if _, err := time.Parse(layout, value); err == nil {
+			return
+		}
+	}
+	v.add(property, fmt.Sprintf("%q is not an ISO 8601 date", value))
+}
+
+func isSchemaContext(context string) bool {
+	context = strings.TrimSuffix(context, "/")
+	return context == Context || context == "http://schema.org"
+}
+
+func hasType(nodes []Node, expected []string) bool {
+	for _, node := range nodes {
+		for _, typ := range expected {
+			if node.SchemaType() == typ {
+				return true
+			}
+		}
+	}
+	return false
+}
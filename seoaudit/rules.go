@@ -0,0 +1,213 @@
+package seoaudit
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultRules returns every built-in rule with its default thresholds
+func DefaultRules() []Rule {
+	return []Rule{
+		TitleLength{Min: 30, Max: 60},
+		DescriptionLength{Min: 120, Max: 160},
+		KeywordInH1{},
+		KeywordInFirstParagraph{},
+		KeywordInSlug{},
+		HeadingHierarchy{},
+		ImageAlt{},
+		LinkCounts{MinInternal: 1, MinExternal: 1},
+		DuplicateHeadings{},
+	}
+}
+
+// TitleLength checks the length of the SEO title in characters
+type TitleLength struct {
+	Min int
+	Max int
+}
+
+func (r TitleLength) Name() string { return "title-length" }
+
+func (r TitleLength) Check(page *Page) []Finding {
+	return checkLength(r.Name(), "title", page.Title, r.Min, r.Max)
+}
+
+// DescriptionLength checks the length of the meta description in characters
+type DescriptionLength struct {
+	Min int
+	Max int
+}
+
+func (r DescriptionLength) Name() string { return "description-length" }
+
+func (r DescriptionLength) Check(page *Page) []Finding {
+	return checkLength(r.Name(), "meta description", page.Description, r.Min, r.Max)
+}
+
+func checkLength(rule, field, text string, minLen, maxLen int) []Finding {
+	n := utf8.RuneCountInString(strings.TrimSpace(text))
+	switch {
+	case n == 0:
+		return []Finding{{Rule: rule, Severity: SeverityError, Message: fmt.Sprintf("%s is missing", field)}}
+	case minLen > 0 && n < minLen:
+		return []Finding{{Rule: rule, Severity: SeverityWarning, Message: fmt.Sprintf("%s is %d characters, shorter than %d", field, n, minLen)}}
+	case maxLen > 0 && n > maxLen:
+		return []Finding{{Rule: rule, Severity: SeverityWarning, Message: fmt.Sprintf("%s is %d characters, longer than %d", field, n, maxLen)}}
+	}
+	return nil
+}
+
+// KeywordInH1 checks that the keyword appears in the first <h1>
+type KeywordInH1 struct{}
+
+func (r KeywordInH1) Name() string { return "keyword-in-h1" }
+
+func (r KeywordInH1) Check(page *Page) []Finding {
+	if page.Keyword == "" {
+		return nil
+	}
+	for _, heading := range page.Headings {
+		if heading.Level == 1 {
+			if page.ContainsKeyword(heading.Text) {
+				return nil
+			}
+			break
+		}
+	}
+	return []Finding{{Severity: SeverityWarning, Message: fmt.Sprintf("keyword %q does not appear in the H1", page.Keyword)}}
+}
+
+// KeywordInFirstParagraph checks that the keyword appears in the first paragraph
+type KeywordInFirstParagraph struct{}
+
+func (r KeywordInFirstParagraph) Name() string { return "keyword-in-first-paragraph" }
+
+func (r KeywordInFirstParagraph) Check(page *Page) []Finding {
+	if page.Keyword == "" || page.ContainsKeyword(page.FirstParagraph) {
+		return nil
+	}
+	return []Finding{{Severity: SeverityWarning, Message: fmt.Sprintf("keyword %q does not appear in the first paragraph", page.Keyword)}}
+}
+
+// KeywordInSlug checks that the keyword appears in the last segment of the
+// page URL. It is skipped when no URL is given.
+type KeywordInSlug struct{}
+
+func (r KeywordInSlug) Name() string { return "keyword-in-slug" }
+
+func (r KeywordInSlug) Check(page *Page) []Finding {
+	if page.Keyword == "" || page.URL == nil {
+		return nil
+	}
+	slug := path.Base(strings.TrimSuffix(page.URL.Path, "/"))
+	if page.ContainsKeyword(slug) {
+		return nil
+	}
+	return []Finding{{Severity: SeverityWarning, Message: fmt.Sprintf("keyword %q does not appear in the URL slug %q", page.Keyword, slug)}}
+}
+
+// HeadingHierarchy checks for exactly one <h1> and for skipped heading levels
+type HeadingHierarchy struct{}
+
+func (r HeadingHierarchy) Name() string { return "heading-hierarchy" }
+
+func (r HeadingHierarchy) Check(page *Page) []Finding {
+	var findings []Finding
+	h1 := 0
+	previous := 0
+	for _, heading := range page.Headings {
+		if heading.Level == 1 {
+			h1++
+		}
+		if previous > 0 && heading.Level > previous+1 {
+			findings = append(findings, Finding{Severity: SeverityWarning,
+				Message: fmt.Sprintf("heading %q jumps from H%d to H%d", heading.Text, previous, heading.Level)})
+		}
+		previous = heading.Level
+	}
+
+	switch {
+	case h1 == 0:
+		findings = append(findings, Finding{Severity: SeverityError, Message: "page has no H1"})
+	case h1 > 1:
+		findings = append(findings, Finding{Severity: SeverityWarning, Message: fmt.Sprintf("page has %d H1 headings", h1)})
+	}
+	return findings
+}
+
+// ImageAlt checks that every image has alt text
+type ImageAlt struct{}
+
+func (r ImageAlt) Name() string { return "image-alt" }
+
+func (r ImageAlt) Check(page *Page) []Finding {
+	var findings []Finding
+	for _, image := range page.Images {
+		switch {
+		case !image.HasAlt:
+			findings = append(findings, Finding{Severity: SeverityWarning, Message: fmt.Sprintf("image %q has no alt attribute", image.Src)})
+		case image.Alt == "":
+			findings = append(findings, Finding{Severity: SeverityInfo, Message: fmt.Sprintf("image %q has empty alt text", image.Src)})
+		}
+	}
+	return findings
+}
+
+// LinkCounts checks the number of internal and external links. A zero
+// maximum means no limit.
+type LinkCounts struct {
+	MinInternal int
+	MaxInternal int
+	MinExternal int
+	MaxExternal int
+}
+
+func (r LinkCounts) Name() string { return "link-counts" }
+
+func (r LinkCounts) Check(page *Page) []Finding {
+	internal, external := 0, 0
+	for _, link := range page.Links {
+		switch {
+		case link.External:
+			external++
+		case link.Internal:
+			internal++
+		}
+	}
+
+	var findings []Finding
+	check := func(kind string, n, low, high int) {
+		switch {
+		case n < low:
+			findings = append(findings, Finding{Severity: SeverityWarning, Message: fmt.Sprintf("%d %s links, fewer than %d", n, kind, low)})
+		case high > 0 && n > high:
+			findings = append(findings, Finding{Severity: SeverityWarning, Message: fmt.Sprintf("%d %s links, more than %d", n, kind, high)})
+		}
+	}
+	check("internal", internal, r.MinInternal, r.MaxInternal)
+	check("external", external, r.MinExternal, r.MaxExternal)
+	return findings
+}
+
+// DuplicateHeadings checks that no two headings share the same text
+type DuplicateHeadings struct{}
+
+func (r DuplicateHeadings) Name() string { return "duplicate-headings" }
+
+func (r DuplicateHeadings) Check(page *Page) []Finding {
+	var findings []Finding
+	seen := make(map[string]int)
+	for _, heading := range page.Headings {
+		key := normalize(heading.Text)
+		if key == "" {
+			continue
+		}
+		seen[key]++
+		if seen[key] == 2 {
+			findings = append(findings, Finding{Severity: SeverityWarning, Message: fmt.Sprintf("heading %q is used more than once", heading.Text)})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,235 @@
+// Package seoaudit checks finished articles against an SEO checklist.
+//
+// Audit parses the article's HTML into a Page and runs each Rule over it.
+// Rules report Findings with a severity; the Report's score is the share of
+// rules that passed, with warnings earning half credit.
+package seoaudit
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"strings"
+
+	"github.com/pushkarsingh32/semanticpen-go-sdk"
+	"github.com/pushkarsingh32/semanticpen-go-sdk/internal/htmlnode"
+)
+
+// ErrNoHTML is returned by Audit when the article has no HTML to inspect
+var ErrNoHTML = errors.New("seoaudit: article has no HTML content")
+
+// Severity ranks a finding
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"    // Worth knowing; does not affect the score
+	SeverityWarning Severity = "warning" // Should be fixed; the rule earns half credit
+	SeverityError   Severity = "error"   // Must be fixed; the rule earns no credit
+)
+
+// Finding is a single problem reported by a rule
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// Report is the outcome of an audit
+type Report struct {
+	Score    int       `json:"score"` // 0-100
+	Findings []Finding `json:"findings,omitempty"`
+}
+
+// Rule is one item of the checklist
+type Rule interface {
+	Name() string
+	Check(page *Page) []Finding
+}
+
+// Options configures Audit
+type Options struct {
+	Keyword string // Target keyword; defaults to the first SEOData keyword
+	URL     string // Published URL, used for the slug check and to tell internal links from external ones
+	Rules   []Rule // Defaults to DefaultRules()
+}
+
+// Page is the parsed view of an article that rules inspect
+type Page struct {
+	Article        *semanticpen.Article
+	Keyword        string
+	URL            *url.URL // Nil when Options.URL was empty or invalid
+	Title          string   // SEOData.Title, falling back to Article.Title
+	Description    string
+	Headings       []semanticpen.Heading
+	FirstParagraph string
+	Images         []Image
+	Links          []Link
+}
+
+// Image is an <img> element on the page
+type Image struct {
+	Src    string
+	Alt    string
+	HasAlt bool // Alt was present, possibly empty for a decorative image
+}
+
+// Link is an <a href> element on the page
+type Link struct {
+	Href     string
+	Text     string
+	External bool // An http(s) link to another site
+	Internal bool // A relative link, or an http(s) link to the site at Page.URL
+}
+
+// Audit checks article against the configured rules
+func Audit(article *semanticpen.Article, options *Options) (*Report, error) {
+	if article == nil {
+		return nil, &semanticpen.ValidationError{Field: "article", Message: "article is required"}
+	}
+	if article.ArticleHTML == "" {
+		return nil, ErrNoHTML
+	}
+	if options == nil {
+		options = &Options{}
+	}
+	rules := options.Rules
+	if rules == nil {
+		rules = DefaultRules()
+	}
+
+	page := NewPage(article, options.Keyword, options.URL)
+
+	report := &Report{}
+	var credit float64
+	for _, rule := range rules {
+		findings := rule.Check(page)
+		worst := SeverityInfo
+		for _, finding := range findings {
+			if finding.Rule == "" {
+				finding.Rule = rule.Name()
+			}
+			report.Findings = append(report.Findings, finding)
+			if finding.Severity == SeverityError || (finding.Severity == SeverityWarning && worst == SeverityInfo) {
+				worst = finding.Severity
+			}
+		}
+
+		switch worst {
+		case SeverityInfo:
+			credit++
+		case SeverityWarning:
+			credit += 0.5
+		}
+	}
+
+	report.Score = 100
+	if len(rules) > 0 {
+		report.Score = int(math.Round(credit / float64(len(rules)) * 100))
+	}
+	return report, nil
+}
+
+// NewPage parses an article for rules to inspect. keyword defaults to the
+// first SEOData keyword; rawURL may be empty.
+func NewPage(article *semanticpen.Article, keyword, rawURL string) *Page {
+	page := &Page{Article: article, Keyword: strings.TrimSpace(keyword), Title: article.Title}
+	if seo := article.SEOData; seo != nil {
+		if seo.Title != "" {
+			page.Title = seo.Title
+		}
+		page.Description = seo.Description
+		if page.Keyword == "" && len(seo.Keywords) > 0 {
+			page.Keyword = strings.TrimSpace(seo.Keywords[0])
+		}
+	}
+	if rawURL != "" {
+		if u, err := url.Parse(rawURL); err == nil && u.IsAbs() {
+			page.URL = u
+		}
+	}
+
+	doc := htmlnode.Parse(article.ArticleHTML)
+	htmlnode.Walk(doc, func(n *htmlnode.Node) bool {
+		if n.Type != htmlnode.ElementNode {
+			return true
+		}
+		switch n.Data {
+		case "script", "style", "template", "noscript":
+			return false
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			page.Headings = append(page.Headings, semanticpen.Heading{Text: cleanText(n.Text()), Level: int(n.Data[1] - '0')})
+		case "p":
+			if page.FirstParagraph == "" {
+				page.FirstParagraph = cleanText(n.Text())
+			}
+		case "img":
+			alt, ok := n.AttrVal("alt")
+			page.Images = append(page.Images, Image{Src: n.Get("src"), Alt: strings.TrimSpace(alt), HasAlt: ok})
+		case "a":
+			if href := strings.TrimSpace(n.Get("href")); href != "" {
+				page.Links = append(page.Links, Link{
+					Href:     href,
+					Text:     cleanText(n.Text()),
+					External: page.isExternal(href),
+					Internal: page.isInternal(href),
+				})
+			}
+		}
+		return true
+	})
+
+	return page
+}
+
+// isExternal reports whether href leaves the site at Page.URL. Without a URL
+// every absolute http(s) link counts as external.
+func (p *Page) isExternal(href string) bool {
+	u, err := url.Parse(href)
+	if err != nil || u.Host == "" || (u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return p.URL == nil || !p.sameSite(u)
+}
+
+// isInternal reports whether href points into the site at Page.URL: a
+// relative link other than a bare fragment, or an http(s) link to the same
+// host. Links with other schemes, such as mailto: or tel:, are neither
+// internal nor external.
+func (p *Page) isInternal(href string) bool {
+	u, err := url.Parse(href)
+	switch {
+	case err != nil:
+		return false
+	case u.Scheme == "" && u.Host == "":
+		return u.Path != "" || u.RawQuery != ""
+	case u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https":
+		return false
+	}
+	return p.URL != nil && p.sameSite(u)
+}
+
+// sameSite reports whether u is on Page.URL's host, ignoring a www. prefix
+func (p *Page) sameSite(u *url.URL) bool {
+	return strings.EqualFold(strings.TrimPrefix(u.Hostname(), "www."), strings.TrimPrefix(p.URL.Hostname(), "www."))
+}
+
+// ContainsKeyword reports whether text contains the page keyword, ignoring
+// case, punctuation and repeated whitespace
+func (p *Page) ContainsKeyword(text string) bool {
+	keyword := normalize(p.Keyword)
+	if keyword == "" {
+		return false
+	}
+	return strings.Contains(" "+normalize(text)+" ", " "+keyword+" ")
+}
+
+func cleanText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// normalize lowercases text and collapses everything but letters and digits to single spaces
+func normalize(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127)
+	}), " ")
+}
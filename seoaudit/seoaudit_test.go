@@ -0,0 +1,64 @@
+package seoaudit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pushkarsingh32/semanticpen-go-sdk"
+)
+
+func TestLinkClassification(t *testing.T) {
+	tests := []struct {
+		href     string
+		pageURL  string
+		internal bool
+		external bool
+	}{
+		{href: "/blog/post", pageURL: "https://example.com/a", internal: true},
+		{href: "post", internal: true},
+		{href: "?page=2", internal: true},
+		{href: "#section"},
+		{href: "https://example.com/x", pageURL: "https://www.example.com/a", internal: true},
+		{href: "//example.com/x", pageURL: "https://example.com/a", internal: true},
+		{href: "HTTPS://Example.com/x", pageURL: "https://example.com/a", internal: true},
+		{href: "https://other.example/x", pageURL: "https://example.com/a", external: true},
+		{href: "https://example.com/x", external: true},
+		{href: "mailto:team@example.com", pageURL: "https://example.com/a"},
+		{href: "tel:+15551234567", pageURL: "https://example.com/a"},
+		{href: "javascript:void(0)", pageURL: "https://example.com/a"},
+		{href: "ftp://example.com/file", pageURL: "https://example.com/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.href, func(t *testing.T) {
+			page := NewPage(&semanticpen.Article{ArticleHTML: `<p><a href="` + tt.href + `">link</a></p>`}, "", tt.pageURL)
+			if len(page.Links) != 1 {
+				t.Fatalf("Links = %+v, want one link", page.Links)
+			}
+			if link := page.Links[0]; link.Internal != tt.internal || link.External != tt.external {
+				t.Errorf("Internal = %v, External = %v; want %v, %v", link.Internal, link.External, tt.internal, tt.external)
+			}
+		})
+	}
+}
+
+func TestLinkCountsIgnoresNonWebLinks(t *testing.T) {
+	html := `<p><a href="mailto:a@example.com">mail</a> <a href="tel:123">call</a>
+		<a href="javascript:void(0)">js</a> <a href="#top">top</a> <a href="https://other.example">ref</a></p>`
+	page := NewPage(&semanticpen.Article{ArticleHTML: html}, "", "https://example.com/post")
+
+	findings := LinkCounts{MinInternal: 1}.Check(page)
+	if len(findings) != 1 || findings[0].Message != "0 internal links, fewer than 1" {
+		t.Errorf("Check() = %+v, want a missing internal link finding", findings)
+	}
+}
+
+func TestAuditErrors(t *testing.T) {
+	var validationErr *semanticpen.ValidationError
+	if _, err := Audit(nil, nil); !errors.As(err, &validationErr) || validationErr.Field != "article" {
+		t.Errorf("Audit(nil) error = %v, want an article ValidationError", err)
+	}
+	if _, err := Audit(&semanticpen.Article{}, nil); !errors.Is(err, ErrNoHTML) {
+		t.Errorf("Audit(empty) error = %v, want ErrNoHTML", err)
+	}
+}
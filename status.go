@@ -0,0 +1,80 @@
+package semanticpen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ArticleStatus is the generation status of an article. Values the SDK does
+// not recognize are preserved as-is so callers can still inspect them.
+type ArticleStatus string
+
+const (
+	StatusPending    ArticleStatus = "pending"
+	StatusProcessing ArticleStatus = "processing"
+	StatusFinished   ArticleStatus = "finished"
+	StatusFailed     ArticleStatus = "failed"
+)
+
+// String returns the status as sent by the API
+func (s ArticleStatus) String() string {
+	return string(s)
+}
+
+// IsKnown reports whether the status is one of the documented values
+func (s ArticleStatus) IsKnown() bool {
+	return s.IsActive() || s.IsTerminal()
+}
+
+// IsTerminal reports whether generation has ended, successfully or not
+func (s ArticleStatus) IsTerminal() bool {
+	return s == StatusFinished || s == StatusFailed
+}
+
+// IsActive reports whether generation is still queued or running
+func (s ArticleStatus) IsActive() bool {
+	return s == StatusPending || s == StatusProcessing
+}
+
+// CanTransitionTo reports whether moving from s to next is a valid step in
+// the generation lifecycle: pending -> processing -> finished | failed.
+// Staying in the same status is always valid; unknown statuses never are.
+func (s ArticleStatus) CanTransitionTo(next ArticleStatus) bool {
+	if !s.IsKnown() || !next.IsKnown() {
+		return false
+	}
+	if s == next {
+		return true
+	}
+
+	switch s {
+	case StatusPending:
+		return true
+	case StatusProcessing:
+		return next.IsTerminal()
+	}
+	return false
+}
+
+// UnmarshalJSON accepts a JSON string (or null), normalizing case and
+// surrounding whitespace. Unrecognized values are kept verbatim.
+func (s *ArticleStatus) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*s = ""
+		return nil
+	}
+
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid article status %s: %w", string(data), err)
+	}
+
+	status := ArticleStatus(strings.ToLower(strings.TrimSpace(raw)))
+	if !status.IsKnown() {
+		status = ArticleStatus(raw)
+	}
+	*s = status
+	return nil
+}
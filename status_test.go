@@ -0,0 +1,128 @@
+package semanticpen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestArticleStatusUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ArticleStatus
+		wantErr bool
+	}{
+		{input: `"finished"`, want: StatusFinished},
+		{input: `"  Processing "`, want: StatusProcessing},
+		{input: `"PENDING"`, want: StatusPending},
+		{input: `null`, want: ""},
+		{input: `"Queued"`, want: ArticleStatus("Queued")},
+		{input: `""`, want: ""},
+		{input: `3`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var article struct {
+				Status ArticleStatus `json:"status"`
+			}
+			err := json.Unmarshal([]byte(`{"status": `+tt.input+`}`), &article)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && article.Status != tt.want {
+				t.Errorf("status = %q, want %q", article.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestArticleStatusCanTransitionTo(t *testing.T) {
+	unknown := ArticleStatus("queued")
+	tests := []struct {
+		from, to ArticleStatus
+		want     bool
+	}{
+		{StatusPending, StatusPending, true},
+		{StatusPending, StatusProcessing, true},
+		{StatusPending, StatusFinished, true},
+		{StatusPending, StatusFailed, true},
+		{StatusProcessing, StatusProcessing, true},
+		{StatusProcessing, StatusFinished, true},
+		{StatusProcessing, StatusFailed, true},
+		{StatusProcessing, StatusPending, false},
+		{StatusFinished, StatusFinished, true},
+		{StatusFinished, StatusProcessing, false},
+		{StatusFinished, StatusFailed, false},
+		{StatusFailed, StatusPending, false},
+		{StatusFailed, StatusFinished, false},
+		{StatusPending, unknown, false},
+		{unknown, StatusFinished, false},
+		{unknown, unknown, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%q.CanTransitionTo(%q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+// articleServer serves article a1 with the given statuses, one per request,
+// repeating the last status once they run out
+func articleServer(t *testing.T, statuses ...string) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&requests, 1))
+		if n > len(statuses) {
+			n = len(statuses)
+		}
+		fmt.Fprintf(w, `{"id": "a1", "status": %q}`, statuses[n-1])
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestWaitForArticleStatusErrors(t *testing.T) {
+	t.Run("unknown status", func(t *testing.T) {
+		server, _ := articleServer(t, "pending", "queued")
+		client := NewClient("key", &Config{BaseURL: server.URL})
+
+		_, err := client.WaitForArticleWithContext(context.Background(), "a1", &GenerateAndWaitOptions{Interval: time.Millisecond})
+		var unknown *UnknownStatusError
+		if !errors.As(err, &unknown) || unknown.ArticleID != "a1" || unknown.Status != "queued" {
+			t.Errorf("WaitForArticleWithContext() error = %v, want UnknownStatusError for queued", err)
+		}
+	})
+
+	t.Run("status regression", func(t *testing.T) {
+		server, requests := articleServer(t, "pending", "processing", "pending", "finished")
+		client := NewClient("key", &Config{BaseURL: server.URL})
+
+		_, err := client.WaitForArticleWithContext(context.Background(), "a1", &GenerateAndWaitOptions{Interval: time.Millisecond})
+		var regression *StatusRegressionError
+		if !errors.As(err, &regression) || regression.Previous != StatusProcessing || regression.Status != StatusPending {
+			t.Errorf("WaitForArticleWithContext() error = %v, want StatusRegressionError from processing to pending", err)
+		}
+		if *requests != 3 {
+			t.Errorf("%d requests, want polling to stop at the regression", *requests)
+		}
+	})
+
+	t.Run("valid lifecycle", func(t *testing.T) {
+		server, _ := articleServer(t, "Pending", "processing", "processing", "FINISHED")
+		client := NewClient("key", &Config{BaseURL: server.URL})
+
+		article, err := client.WaitForArticleWithContext(context.Background(), "a1", &GenerateAndWaitOptions{Interval: time.Millisecond})
+		if err != nil || article.Status != StatusFinished {
+			t.Errorf("WaitForArticleWithContext() = %+v, %v; want the finished article", article, err)
+		}
+	})
+}
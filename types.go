@@ -12,6 +12,11 @@ type GenerateArticleRequest struct {
 	SEO           *SEOOptions           `json:"seo,omitempty"`
 	Writing       *WritingOptions       `json:"writing,omitempty"`
 	Advanced      map[string]interface{} `json:"advanced,omitempty"`
+	WebhookURL    string                 `json:"webhookUrl,omitempty"` // Receives a signed notification when generation ends; see package webhook
+
+	// IdempotencyKey is sent as the Idempotency-Key header so that a repeated
+	// request is not billed twice. One is generated automatically when retries are enabled.
+	IdempotencyKey string `json:"-"`
 }
 
 // GenerationOptions contains options for article generation
@@ -62,11 +67,25 @@ func (r *GenerateArticleResponse) GetArticleID() (string, error) {
 	return "", fmt.Errorf("no articles were generated")
 }
 
+// GetArticleIDs returns every article ID in the response, handling both formats
+func (r *GenerateArticleResponse) GetArticleIDs() []string {
+	ids := make([]string, 0, len(r.ArticleIDs)+1)
+	if r.ArticleID != "" {
+		ids = append(ids, r.ArticleID)
+	}
+	for _, id := range r.ArticleIDs {
+		if id != "" && id != r.ArticleID {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 // Article represents an article with its status and content
 type Article struct {
 	ID           string                 `json:"id"`
 	ProjectID    string                 `json:"projectId"`
-	Status       string                 `json:"status"`
+	Status       ArticleStatus          `json:"status"`
 	Progress     int                    `json:"progress"`
 	Title        string                 `json:"title,omitempty"`
 	ArticleHTML  string                 `json:"article_html,omitempty"`
@@ -87,13 +106,76 @@ type SEOData struct {
 
 // GenerateAndWaitOptions contains options for the generate and wait method
 type GenerateAndWaitOptions struct {
-	MaxAttempts    int                                    `json:"maxAttempts,omitempty"`
-	Interval       time.Duration                          `json:"interval,omitempty"`
-	OnProgress     func(attempt int, status string)       `json:"-"`
+	MaxAttempts     int                              `json:"maxAttempts,omitempty"` // Unlimited when zero and Timeout is set
+	Interval        time.Duration                    `json:"interval,omitempty"`    // Used when PollStrategy is nil
+	Timeout         time.Duration                    `json:"timeout,omitempty"`     // Overall deadline for the wait
+	PollStrategy    PollStrategy                     `json:"-"`                     // Decides the delay between checks; defaults to FixedPoll{Interval}
+	OnProgress      func(attempt int, status string) `json:"-"`
+	OnProgressEvent func(event ProgressEvent)        `json:"-"` // Receives the full article snapshot on every check
+	Sanitize        *SanitizePolicy                  `json:"-"` // Sanitize the finished article's HTML with this policy
+}
+
+const (
+	DefaultWaitMaxAttempts = 60
+	DefaultWaitInterval    = 5 * time.Second
+)
+
+// withDefaults returns a copy of the options with zero values filled in, so
+// callers' options are never modified and can be shared between goroutines
+func (o *GenerateAndWaitOptions) withDefaults() *GenerateAndWaitOptions {
+	options := GenerateAndWaitOptions{}
+	if o != nil {
+		options = *o
+	}
+
+	if options.MaxAttempts == 0 && options.Timeout == 0 {
+		options.MaxAttempts = DefaultWaitMaxAttempts
+	}
+	if options.Interval == 0 {
+		options.Interval = DefaultWaitInterval
+	}
+	if options.PollStrategy == nil {
+		options.PollStrategy = FixedPoll{Interval: options.Interval}
+	}
+	return &options
 }
 
 // PollingOptions contains options for polling operations
 type PollingOptions struct {
 	MaxAttempts int           `json:"maxAttempts,omitempty"`
 	Interval    time.Duration `json:"interval,omitempty"`
-}
\ No newline at end of file
+}
+
+// ListArticlesOptions filters and paginates article listings
+type ListArticlesOptions struct {
+	ProjectID     string        `json:"projectId,omitempty"`
+	Status        ArticleStatus `json:"status,omitempty"`
+	CreatedAfter  time.Time     `json:"createdAfter,omitempty"`
+	CreatedBefore time.Time     `json:"createdBefore,omitempty"`
+	Keyword       string        `json:"keyword,omitempty"`
+	Limit         int           `json:"limit,omitempty"`  // Page size; the API default applies when zero
+	Cursor        string        `json:"cursor,omitempty"` // Opaque cursor from a previous page's NextCursor
+	Offset        int           `json:"offset,omitempty"` // Used when the API paginates by offset
+}
+
+// ArticleList represents one page of articles
+type ArticleList struct {
+	Articles   []Article `json:"articles"`
+	NextCursor string    `json:"nextCursor,omitempty"`
+	HasMore    bool      `json:"hasMore,omitempty"`
+	Total      int       `json:"total,omitempty"`
+}
+
+// WaitForArticlesOptions contains options for waiting on several articles at once
+type WaitForArticlesOptions struct {
+	Concurrency int                     `json:"concurrency,omitempty"` // Articles polled at the same time; defaults to DefaultWaitConcurrency
+	FailFast    bool                    `json:"failFast,omitempty"`    // Cancel the remaining waits as soon as one article fails
+	Wait        *GenerateAndWaitOptions `json:"wait,omitempty"`        // Polling options applied to each article
+}
+
+// ArticleResult is the outcome of waiting for a single article
+type ArticleResult struct {
+	ArticleID string   `json:"articleId"`
+	Article   *Article `json:"article,omitempty"`
+	Err       error    `json:"-"`
+}
@@ -0,0 +1,116 @@
+package semanticpen
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultWaitConcurrency is the number of articles polled at the same time
+// when WaitForArticlesOptions.Concurrency is not set
+const DefaultWaitConcurrency = 4
+
+// WaitForArticles waits for every article in ids to complete, polling up to
+// options.Concurrency of them at a time. Results are returned in the same order
+// as ids, each carrying either the finished article or its error.
+//
+// The returned error is nil only if every article finished. Otherwise it is the
+// first failure observed; with FailFast set, the remaining waits are cancelled
+// and their results carry the cancellation error.
+func (c *Client) WaitForArticles(ctx context.Context, ids []string, options *WaitForArticlesOptions) ([]ArticleResult, error) {
+	if len(ids) == 0 {
+		return nil, &ValidationError{
+			Field:   "ids",
+			Message: "at least one article ID is required",
+		}
+	}
+	for _, id := range ids {
+		if id == "" {
+			return nil, &ValidationError{
+				Field:   "ids",
+				Message: "article IDs must not be empty",
+			}
+		}
+	}
+
+	if options == nil {
+		options = &WaitForArticlesOptions{}
+	}
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultWaitConcurrency
+	}
+	if concurrency > len(ids) {
+		concurrency = len(ids)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]ArticleResult, len(ids))
+	jobs := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				article, err := c.WaitForArticleWithContext(ctx, ids[i], options.Wait)
+				results[i] = ArticleResult{ArticleID: ids[i], Article: article, Err: err}
+				if err == nil {
+					continue
+				}
+
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("article %s: %w", ids[i], err)
+					if options.FailFast {
+						cancel()
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for i := range ids {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			for j := i; j < len(ids); j++ {
+				results[j] = ArticleResult{ArticleID: ids[j], Err: ctx.Err()}
+			}
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return results, firstErr
+}
+
+// GenerateArticlesAndWait generates articles for the target keyword and waits
+// for every article ID the API returns, not just the first
+func (c *Client) GenerateArticlesAndWait(ctx context.Context, targetKeyword string, options *GenerateArticleRequest, waitOptions *WaitForArticlesOptions) ([]ArticleResult, error) {
+	result, err := c.GenerateArticleWithContext(ctx, targetKeyword, options)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := result.GetArticleIDs()
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no articles were generated")
+	}
+
+	return c.WaitForArticles(ctx, ids, waitOptions)
+}
@@ -0,0 +1,375 @@
+package semanticpen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultWatchConcurrency is the number of status requests a Watcher makes at
+// the same time when WatcherOptions.Concurrency is not set
+const DefaultWatchConcurrency = 4
+
+// DefaultWatchMaxErrors is the number of consecutive failed polls after which
+// a Watcher gives up on an article when WatcherOptions.MaxErrors is not set
+const DefaultWatchMaxErrors = 5
+
+// ErrWatcherClosed is returned by Watch after the watcher has been closed
+var ErrWatcherClosed = errors.New("watcher closed")
+
+// WatcherOptions configures a Watcher
+type WatcherOptions struct {
+	Interval    time.Duration // Time between polling rounds; defaults to DefaultWaitInterval
+	Concurrency int           // Status requests in flight per round; defaults to DefaultWatchConcurrency
+	MaxErrors   int           // Consecutive failed polls before giving up on an article; defaults to DefaultWatchMaxErrors
+}
+
+// Watcher tracks many articles with a single shared polling loop. Each article
+// is fetched once per round no matter how many subscribers watch it, and every
+// subscriber receives the latest snapshot on its channel. Channels are closed
+// when the article reaches a terminal status, the article no longer exists,
+// polling fails with a client error or too many times in a row, the
+// subscriber's context is done, or the watcher is closed. Err reports why an
+// article's channels were closed early.
+//
+// Subscriber channels hold only the most recent snapshot: a slow reader skips
+// intermediate updates but always receives the terminal one.
+type Watcher struct {
+	client      *Client
+	interval    time.Duration
+	concurrency int
+	maxErrors   int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wake   chan struct{}
+
+	mu       sync.Mutex
+	subs     map[string][]*subscription
+	failures map[string]int   // Consecutive failed polls per watched article
+	errs     map[string]error // Errors that ended an article's subscriptions
+	running  bool
+	closed   bool
+}
+
+// NewWatcher creates a watcher that polls with the given client
+func NewWatcher(client *Client, options *WatcherOptions) *Watcher {
+	if options == nil {
+		options = &WatcherOptions{}
+	}
+
+	w := &Watcher{
+		client:      client,
+		interval:    options.Interval,
+		concurrency: options.Concurrency,
+		maxErrors:   options.MaxErrors,
+		wake:        make(chan struct{}, 1),
+		subs:        make(map[string][]*subscription),
+		failures:    make(map[string]int),
+		errs:        make(map[string]error),
+	}
+	if w.interval <= 0 {
+		w.interval = DefaultWaitInterval
+	}
+	if w.concurrency <= 0 {
+		w.concurrency = DefaultWatchConcurrency
+	}
+	if w.maxErrors <= 0 {
+		w.maxErrors = DefaultWatchMaxErrors
+	}
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	return w
+}
+
+// WatchArticle streams snapshots of a single article until it reaches a
+// terminal status, polling gives up, or ctx is done, at which point the
+// channel is closed and the underlying Watcher is released
+func (c *Client) WatchArticle(ctx context.Context, articleID string) (<-chan *Article, error) {
+	w := NewWatcher(c, nil)
+	sub, err := w.subscribe(ctx, articleID)
+	if err != nil {
+		w.Close()
+		return nil, err
+	}
+
+	go func() {
+		<-sub.done
+		w.Close()
+	}()
+	return sub.ch, nil
+}
+
+// Watch subscribes to snapshots of an article. The returned channel is closed
+// when the article is finished or failed, when polling gives up (see Err), or
+// when ctx is done.
+func (w *Watcher) Watch(ctx context.Context, articleID string) (<-chan *Article, error) {
+	sub, err := w.subscribe(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	return sub.ch, nil
+}
+
+// Err returns the error that made the watcher give up on an article, or nil
+// if its channels were closed for any other reason or are still open. It is
+// reset by the next Watch of the article.
+func (w *Watcher) Err(articleID string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.errs[articleID]
+}
+
+// subscribe registers a subscriber for an article and starts the polling loop
+// if it is not running
+func (w *Watcher) subscribe(ctx context.Context, articleID string) (*subscription, error) {
+	if articleID == "" {
+		return nil, &ValidationError{
+			Field:   "articleID",
+			Message: "article ID is required",
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sub := &subscription{ch: make(chan *Article, 1), done: make(chan struct{})}
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil, ErrWatcherClosed
+	}
+	w.subs[articleID] = append(w.subs[articleID], sub)
+	delete(w.errs, articleID)
+	if !w.running {
+		w.running = true
+		go w.run()
+	}
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			w.unsubscribe(articleID, sub)
+		case <-sub.done:
+		}
+	}()
+
+	return sub, nil
+}
+
+// Close stops polling and closes every subscriber channel
+func (w *Watcher) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	w.cancel()
+
+	for id, subs := range w.subs {
+		for _, sub := range subs {
+			sub.close()
+		}
+		delete(w.subs, id)
+	}
+	return nil
+}
+
+// run is the shared polling loop. It exits once nobody is subscribed and is
+// restarted by the next Watch.
+func (w *Watcher) run() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		ids := w.watchedIDs()
+		if ids == nil {
+			return
+		}
+
+		w.poll(ids)
+
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+	}
+}
+
+// watchedIDs returns the articles with subscribers, or nil after marking the
+// loop as stopped when there are none
+func (w *Watcher) watchedIDs() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.subs) == 0 || w.closed {
+		w.running = false
+		return nil
+	}
+
+	ids := make([]string, 0, len(w.subs))
+	for id := range w.subs {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// poll fetches every watched article once, with bounded concurrency
+func (w *Watcher) poll(ids []string) {
+	sem := make(chan struct{}, w.concurrency)
+	var wg sync.WaitGroup
+
+	for _, id := range ids {
+		select {
+		case sem <- struct{}{}:
+		case <-w.ctx.Done():
+			wg.Wait()
+			return
+		}
+
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			article, err := w.client.GetArticleWithContext(w.ctx, id)
+			if err == nil {
+				w.deliver(id, article)
+				return
+			}
+			if w.ctx.Err() != nil {
+				return
+			}
+
+			var apiErr *APIError
+			switch {
+			case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+				w.client.logger.Warn("semanticpen watched article not found", "article_id", id)
+				w.closeArticle(id, err)
+			case isPermanentError(err):
+				w.client.logger.Warn("semanticpen watch stopped", "article_id", id, "error", err)
+				w.closeArticle(id, err)
+			default:
+				w.fail(id, err)
+			}
+		}(id)
+	}
+	wg.Wait()
+}
+
+// deliver hands the latest snapshot to every subscriber, replacing any
+// snapshot they have not read yet, and closes their channels once the article
+// is terminal
+func (w *Watcher) deliver(articleID string, article *Article) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, sub := range w.subs[articleID] {
+		select {
+		case <-sub.ch:
+		default:
+		}
+		sub.ch <- article
+	}
+
+	delete(w.failures, articleID)
+	if article.Status.IsTerminal() {
+		for _, sub := range w.subs[articleID] {
+			sub.close()
+		}
+		delete(w.subs, articleID)
+	}
+}
+
+// fail counts a failed poll, closing the article's subscribers once the
+// failures exceed maxErrors in a row
+func (w *Watcher) fail(articleID string, err error) {
+	w.mu.Lock()
+	if _, ok := w.subs[articleID]; !ok {
+		w.mu.Unlock()
+		return
+	}
+	w.failures[articleID]++
+	failures := w.failures[articleID]
+	w.mu.Unlock()
+
+	if failures < w.maxErrors {
+		w.client.logger.Warn("semanticpen watch poll failed", "article_id", articleID, "failures", failures, "error", err)
+		return
+	}
+	w.client.logger.Warn("semanticpen watch giving up", "article_id", articleID, "failures", failures, "error", err)
+	w.closeArticle(articleID, err)
+}
+
+// closeArticle closes every subscriber of an article, recording err for Err
+func (w *Watcher) closeArticle(articleID string, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.subs[articleID]; !ok {
+		return
+	}
+	for _, sub := range w.subs[articleID] {
+		sub.close()
+	}
+	delete(w.subs, articleID)
+	delete(w.failures, articleID)
+	w.errs[articleID] = err
+}
+
+// isPermanentError reports whether polling again cannot succeed: a client
+// error other than a request timeout, such as 401 or 403
+func isPermanentError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusRequestTimeout
+}
+
+// unsubscribe removes and closes a single subscriber channel
+func (w *Watcher) unsubscribe(articleID string, sub *subscription) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	subs := w.subs[articleID]
+	for i, s := range subs {
+		if s == sub {
+			sub.close()
+			subs = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+
+	if len(subs) == 0 {
+		delete(w.subs, articleID)
+		delete(w.failures, articleID)
+	} else {
+		w.subs[articleID] = subs
+	}
+}
+
+// subscription is one Watch call's channel; done is closed alongside ch so the
+// goroutine watching the subscriber's context can exit
+type subscription struct {
+	ch   chan *Article
+	done chan struct{}
+}
+
+// close closes the subscriber channel; callers hold Watcher.mu
+func (s *subscription) close() {
+	close(s.ch)
+	close(s.done)
+}
@@ -0,0 +1,130 @@
+package semanticpen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// drain reads ch until it is closed, failing the test if that takes too long
+func drain(t *testing.T, ch <-chan *Article) []*Article {
+	t.Helper()
+	var articles []*Article
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case article, ok := <-ch:
+			if !ok {
+				return articles
+			}
+			articles = append(articles, article)
+		case <-timeout:
+			t.Fatal("channel was not closed")
+		}
+	}
+}
+
+func TestWatcherStops(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantCode int // Status code of the APIError reported by Err; 0 for none
+		wantLast ArticleStatus
+	}{
+		{name: "finished", status: http.StatusOK, body: `{"id": "a1", "status": "finished"}`, wantLast: StatusFinished},
+		{name: "not found", status: http.StatusNotFound, body: `{"message": "not found"}`, wantCode: http.StatusNotFound},
+		{name: "unauthorized", status: http.StatusUnauthorized, body: `{"message": "invalid key"}`, wantCode: http.StatusUnauthorized},
+		{name: "forbidden", status: http.StatusForbidden, body: `{"message": "forbidden"}`, wantCode: http.StatusForbidden},
+		{name: "persistent server error", status: http.StatusBadGateway, body: `{"message": "bad gateway"}`, wantCode: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			watcher := NewWatcher(NewClient("key", &Config{BaseURL: server.URL}), &WatcherOptions{Interval: 5 * time.Millisecond, MaxErrors: 3})
+			defer watcher.Close()
+
+			updates, err := watcher.Watch(context.Background(), "a1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			articles := drain(t, updates)
+
+			if tt.wantLast != "" && (len(articles) == 0 || articles[len(articles)-1].Status != tt.wantLast) {
+				t.Errorf("last snapshot = %v, want status %s", articles, tt.wantLast)
+			}
+			var apiErr *APIError
+			switch err := watcher.Err("a1"); {
+			case tt.wantCode == 0 && err != nil:
+				t.Errorf("Err() = %v, want nil", err)
+			case tt.wantCode != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantCode):
+				t.Errorf("Err() = %v, want API error %d", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestWatcherRecoversFromTransientErrors(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&polls, 1) {
+		case 1, 2, 4, 5:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 3:
+			fmt.Fprint(w, `{"id": "a1", "status": "processing"}`)
+		default:
+			fmt.Fprint(w, `{"id": "a1", "status": "finished"}`)
+		}
+	}))
+	defer server.Close()
+
+	watcher := NewWatcher(NewClient("key", &Config{BaseURL: server.URL}), &WatcherOptions{Interval: 5 * time.Millisecond, MaxErrors: 3})
+	defer watcher.Close()
+
+	updates, err := watcher.Watch(context.Background(), "a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	articles := drain(t, updates)
+	if len(articles) == 0 || articles[len(articles)-1].Status != StatusFinished || watcher.Err("a1") != nil {
+		t.Errorf("snapshots = %v, Err() = %v; want to finish without error", articles, watcher.Err("a1"))
+	}
+}
+
+func TestWatchArticleCancelAbortsRequest(t *testing.T) {
+	started := make(chan struct{})
+	aborted := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+		close(aborted)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates, err := NewClient("key", &Config{BaseURL: server.URL}).WatchArticle(ctx, "a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	<-started
+	cancel()
+	drain(t, updates)
+
+	select {
+	case <-aborted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("in-flight request was not cancelled")
+	}
+}
@@ -0,0 +1,329 @@
+// Package webhook receives SemanticPen completion notifications.
+//
+// Deliveries are POST requests whose JSON body describes an event and carries
+// the article. Each request is signed with HMAC-SHA256 over
+// "<timestamp>.<body>" using the shared secret configured for the webhook; the
+// Handler verifies the signature, rejects stale or replayed deliveries and
+// dispatches the decoded event to registered handlers.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pushkarsingh32/semanticpen-go-sdk"
+)
+
+const (
+	// SignatureHeader carries "sha256=<hex HMAC>" of the signed payload
+	SignatureHeader = "X-SemanticPen-Signature"
+	// TimestampHeader carries the Unix time at which the delivery was signed
+	TimestampHeader = "X-SemanticPen-Timestamp"
+
+	// DefaultTolerance is the maximum accepted clock difference for a delivery
+	DefaultTolerance = 5 * time.Minute
+	// DefaultMaxBodyBytes limits the size of a delivery body
+	DefaultMaxBodyBytes = 10 << 20
+)
+
+// Event types sent by SemanticPen
+const (
+	EventArticleFinished = "article.finished"
+	EventArticleFailed   = "article.failed"
+)
+
+var (
+	ErrEmptySecret      = errors.New("webhook: secret is empty")
+	ErrMissingSignature = errors.New("webhook: missing signature or timestamp")
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	ErrStaleTimestamp   = errors.New("webhook: timestamp outside tolerance")
+)
+
+// Event is a decoded webhook delivery
+type Event struct {
+	ID        string               `json:"id"`
+	Type      string               `json:"type"`
+	CreatedAt time.Time            `json:"createdAt"`
+	Article   *semanticpen.Article `json:"article"`
+}
+
+// HandlerFunc handles a verified event. Returning an error makes the Handler
+// respond with 500 so that the delivery is retried.
+type HandlerFunc func(ctx context.Context, event *Event) error
+
+// Options configures a Handler
+type Options struct {
+	Tolerance    time.Duration      // Maximum age (or clock skew) of a delivery; defaults to DefaultTolerance
+	MaxBodyBytes int64              // Largest accepted body; defaults to DefaultMaxBodyBytes
+	Logger       semanticpen.Logger // Receives rejected deliveries and handler errors
+}
+
+// Handler is an http.Handler that verifies and dispatches webhook deliveries
+type Handler struct {
+	secret       []byte
+	tolerance    time.Duration
+	maxBodyBytes int64
+	logger       semanticpen.Logger
+
+	mu       sync.Mutex
+	handlers map[string][]HandlerFunc
+	fallback []HandlerFunc
+	seen     map[string]replay
+}
+
+// replay tracks a delivery that has been dispatched or is being dispatched
+type replay struct {
+	expires time.Time
+	done    bool // Handlers succeeded; false while they are still running
+}
+
+// NewHandler creates a Handler that verifies deliveries with secret. It
+// returns ErrEmptySecret when secret is empty, since anyone could then sign
+// deliveries.
+func NewHandler(secret string, options *Options) (*Handler, error) {
+	if secret == "" {
+		return nil, ErrEmptySecret
+	}
+	if options == nil {
+		options = &Options{}
+	}
+
+	h := &Handler{
+		secret:       []byte(secret),
+		tolerance:    options.Tolerance,
+		maxBodyBytes: options.MaxBodyBytes,
+		logger:       options.Logger,
+		handlers:     make(map[string][]HandlerFunc),
+		seen:         make(map[string]replay),
+	}
+	if h.tolerance <= 0 {
+		h.tolerance = DefaultTolerance
+	}
+	if h.maxBodyBytes <= 0 {
+		h.maxBodyBytes = DefaultMaxBodyBytes
+	}
+	return h, nil
+}
+
+// On registers fn for events of the given type
+func (h *Handler) On(eventType string, fn HandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[eventType] = append(h.handlers[eventType], fn)
+}
+
+// OnAny registers fn for every event, after the type-specific handlers
+func (h *Handler) OnAny(fn HandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fallback = append(h.fallback, fn)
+}
+
+// ServeHTTP verifies, decodes and dispatches a delivery. Deliveries that were
+// already handled successfully are acknowledged without being dispatched
+// again; a duplicate that arrives while the original is still being handled
+// gets 409 Conflict so that the sender retries it later.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, h.maxBodyBytes+1))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if int64(len(body)) > h.maxBodyBytes {
+		http.Error(w, "body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	signature := r.Header.Get(SignatureHeader)
+	now := time.Now()
+	if err := Verify(h.secret, signature, r.Header.Get(TimestampHeader), body, now, h.tolerance); err != nil {
+		h.logWarn("semanticpen webhook rejected", "error", err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	event, err := decodeEvent(body)
+	if err != nil {
+		h.logWarn("semanticpen webhook payload invalid", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	replayKey := event.ID
+	if replayKey == "" {
+		replayKey = signature
+	}
+	switch h.reserve(replayKey, now) {
+	case replayDone:
+		w.WriteHeader(http.StatusOK)
+		return
+	case replayInFlight:
+		http.Error(w, "delivery is already being handled", http.StatusConflict)
+		return
+	}
+
+	for _, fn := range h.handlersFor(event.Type) {
+		if err := fn(r.Context(), event); err != nil {
+			h.release(replayKey)
+			h.logError("semanticpen webhook handler failed", "event_id", event.ID, "type", event.Type, "error", err)
+			http.Error(w, "handler failed", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	h.complete(replayKey)
+	w.WriteHeader(http.StatusOK)
+}
+
+// Sign returns the signature header value for a body signed at timestamp
+func Sign(secret []byte, timestamp time.Time, body []byte) string {
+	return "sha256=" + hex.EncodeToString(computeMAC(secret, strconv.FormatInt(timestamp.Unix(), 10), body))
+}
+
+// Verify checks a delivery's signature and timestamp headers against body
+func Verify(secret []byte, signature, timestamp string, body []byte, now time.Time, tolerance time.Duration) error {
+	if len(secret) == 0 {
+		return ErrEmptySecret
+	}
+	if signature == "" || timestamp == "" {
+		return ErrMissingSignature
+	}
+
+	unix, err := strconv.ParseInt(strings.TrimSpace(timestamp), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrStaleTimestamp, err)
+	}
+	age := now.Sub(time.Unix(unix, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrStaleTimestamp
+	}
+
+	given, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal(given, computeMAC(secret, strings.TrimSpace(timestamp), body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// computeMAC returns HMAC-SHA256 of "<timestamp>.<body>"
+func computeMAC(secret []byte, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// decodeEvent parses a delivery body. A body holding a bare article is
+// accepted too, with the event type derived from the article status.
+func decodeEvent(body []byte) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+
+	if event.Article == nil {
+		var article semanticpen.Article
+		if err := json.Unmarshal(body, &article); err != nil || article.ID == "" {
+			return nil, errors.New("invalid webhook payload: no article")
+		}
+		event.Article = &article
+		event.ID = ""
+	}
+
+	if event.Type == "" {
+		event.Type = "article." + event.Article.Status.String()
+	}
+	return &event, nil
+}
+
+// handlersFor returns the handlers to run for an event type
+func (h *Handler) handlersFor(eventType string) []HandlerFunc {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	handlers := make([]HandlerFunc, 0, len(h.handlers[eventType])+len(h.fallback))
+	handlers = append(handlers, h.handlers[eventType]...)
+	return append(handlers, h.fallback...)
+}
+
+type replayState int
+
+const (
+	replayNew replayState = iota
+	replayInFlight
+	replayDone
+)
+
+// reserve claims a delivery for dispatch. Only the caller that gets
+// replayNew may run the handlers; the claim is held until complete or release.
+func (h *Handler) reserve(key string, now time.Time) replayState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for k, r := range h.seen {
+		if r.done && now.After(r.expires) {
+			delete(h.seen, k)
+		}
+	}
+	if r, ok := h.seen[key]; ok {
+		if r.done {
+			return replayDone
+		}
+		return replayInFlight
+	}
+
+	// Remember the delivery until its timestamp would be rejected anyway
+	h.seen[key] = replay{expires: now.Add(2 * h.tolerance)}
+	return replayNew
+}
+
+// complete marks a reserved delivery as handled
+func (h *Handler) complete(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if r, ok := h.seen[key]; ok {
+		r.done = true
+		h.seen[key] = r
+	}
+}
+
+// release drops the claim on a delivery whose handlers failed, so that a
+// retry is dispatched again
+func (h *Handler) release(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.seen, key)
+}
+
+func (h *Handler) logWarn(msg string, args ...interface{}) {
+	if h.logger != nil {
+		h.logger.Warn(msg, args...)
+	}
+}
+
+func (h *Handler) logError(msg string, args ...interface{}) {
+	if h.logger != nil {
+		h.logger.Error(msg, args...)
+	}
+}
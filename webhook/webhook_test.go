@@ -0,0 +1,224 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testSecret = "whsec_test"
+
+const testBody = `{"id":"evt_1","type":"article.finished","article":{"id":"art_1","status":"finished"}}`
+
+func signedRequest(t *testing.T, secret string, signedAt time.Time, body string) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/hooks", strings.NewReader(body))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(signedAt.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign([]byte(secret), signedAt, []byte(body)))
+	return req
+}
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(testBody)
+	signature := Sign([]byte(testSecret), now, body)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		timestamp string
+		body      []byte
+		now       time.Time
+		want      error
+	}{
+		{"valid", testSecret, signature, timestamp, body, now, nil},
+		{"valid within tolerance", testSecret, signature, timestamp, body, now.Add(4 * time.Minute), nil},
+		{"wrong secret", "other", signature, timestamp, body, now, ErrInvalidSignature},
+		{"empty secret", "", Sign(nil, now, body), timestamp, body, now, ErrEmptySecret},
+		{"tampered body", testSecret, signature, timestamp, []byte(testBody + " "), now, ErrInvalidSignature},
+		{"tampered timestamp", testSecret, signature, strconv.FormatInt(now.Unix()+1, 10), body, now, ErrInvalidSignature},
+		{"malformed signature", testSecret, "sha256=zz", timestamp, body, now, ErrInvalidSignature},
+		{"missing signature", testSecret, "", timestamp, body, now, ErrMissingSignature},
+		{"missing timestamp", testSecret, signature, "", body, now, ErrMissingSignature},
+		{"stale", testSecret, signature, timestamp, body, now.Add(6 * time.Minute), ErrStaleTimestamp},
+		{"from the future", testSecret, signature, timestamp, body, now.Add(-6 * time.Minute), ErrStaleTimestamp},
+		{"unparseable timestamp", testSecret, signature, "yesterday", body, now, ErrStaleTimestamp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify([]byte(tt.secret), tt.signature, tt.timestamp, tt.body, tt.now, DefaultTolerance)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewHandlerRejectsEmptySecret(t *testing.T) {
+	if _, err := NewHandler("", nil); !errors.Is(err, ErrEmptySecret) {
+		t.Fatalf("NewHandler(\"\") error = %v, want ErrEmptySecret", err)
+	}
+}
+
+func TestHandlerDispatch(t *testing.T) {
+	h, err := NewHandler(testSecret, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	h.On(EventArticleFinished, func(ctx context.Context, e *Event) error {
+		got = append(got, "finished:"+e.Article.ID)
+		return nil
+	})
+	h.OnAny(func(ctx context.Context, e *Event) error {
+		got = append(got, "any:"+e.Type)
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, signedRequest(t, testSecret, time.Now(), testBody))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if strings.Join(got, ",") != "finished:art_1,any:article.finished" {
+		t.Errorf("dispatched %v", got)
+	}
+}
+
+func TestHandlerRejects(t *testing.T) {
+	h, err := NewHandler(testSecret, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.OnAny(func(ctx context.Context, e *Event) error {
+		t.Error("handler called for a rejected delivery")
+		return nil
+	})
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		want int
+	}{
+		{"stale timestamp", signedRequest(t, testSecret, time.Now().Add(-10*time.Minute), testBody), http.StatusUnauthorized},
+		{"wrong secret", signedRequest(t, "other", time.Now(), testBody), http.StatusUnauthorized},
+		{"empty key signature", signedRequest(t, "", time.Now(), testBody), http.StatusUnauthorized},
+		{"unsigned", httptest.NewRequest(http.MethodPost, "/hooks", strings.NewReader(testBody)), http.StatusUnauthorized},
+		{"wrong method", httptest.NewRequest(http.MethodGet, "/hooks", nil), http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, tt.req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlerReplay(t *testing.T) {
+	h, err := NewHandler(testSecret, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls int32
+	h.OnAny(func(ctx context.Context, e *Event) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+
+	signedAt := time.Now()
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, signedRequest(t, testSecret, signedAt, testBody))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("delivery %d: status = %d, want 200", i, rec.Code)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("handler ran %d times for a replayed delivery, want 1", calls)
+	}
+}
+
+func TestHandlerConcurrentReplay(t *testing.T) {
+	h, err := NewHandler(testSecret, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls int32
+	release := make(chan struct{})
+	h.OnAny(func(ctx context.Context, e *Event) error {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return nil
+	})
+
+	signedAt := time.Now()
+	const deliveries = 5
+	codes := make(chan int, deliveries)
+	var wg sync.WaitGroup
+	for i := 0; i < deliveries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, signedRequest(t, testSecret, signedAt, testBody))
+			codes <- rec.Code
+		}()
+	}
+
+	// Every duplicate is turned away while the first is still being handled
+	for i := 0; i < deliveries-1; i++ {
+		if code := <-codes; code != http.StatusConflict {
+			t.Errorf("duplicate status = %d, want 409", code)
+		}
+	}
+	close(release)
+	wg.Wait()
+	if code := <-codes; code != http.StatusOK {
+		t.Errorf("original status = %d, want 200", code)
+	}
+	if calls != 1 {
+		t.Errorf("handler ran %d times for concurrent duplicates, want 1", calls)
+	}
+}
+
+func TestHandlerRetriesAfterFailure(t *testing.T) {
+	h, err := NewHandler(testSecret, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls int32
+	h.OnAny(func(ctx context.Context, e *Event) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+
+	signedAt := time.Now()
+	for i, want := range []int{http.StatusInternalServerError, http.StatusOK, http.StatusOK} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, signedRequest(t, testSecret, signedAt, testBody))
+		if rec.Code != want {
+			t.Errorf("delivery %d: status = %d, want %d", i, rec.Code, want)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("handler ran %d times, want 2 (failure, then one successful retry)", calls)
+	}
+}